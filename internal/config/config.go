@@ -1,25 +1,351 @@
 package config
 
 import (
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Name case rules applied to first and last names on storage
+const (
+	NameCasePreserve = "preserve"
+	NameCaseTitle    = "title"
+	NameCaseUpper    = "upper"
+)
+
+// Modes for the tier reconciliation job
+const (
+	ReconcileModeReport  = "report"
+	ReconcileModeCorrect = "correct"
+)
+
+// What happens to a deduction that would take a balance below its tier's floor
+const (
+	PointsFloorReject = "reject"
+	PointsFloorClamp  = "clamp"
+)
+
+// How PUT /users/:id treats fields left out of the request. Merge leaves them
+// unchanged; replace treats the body as the full set of editable fields.
+const (
+	UpdateSemanticsMerge   = "merge"
+	UpdateSemanticsReplace = "replace"
+)
+
+// Key case used for JSON response fields
+const (
+	JSONCaseSnake = "snake"
+	JSONCaseCamel = "camel"
+)
+
+// How API requests are authenticated
+const (
+	AuthModeNone   = "none"
+	AuthModeAPIKey = "apikey"
+	AuthModeJWT    = "jwt"
+)
+
+// Deployment environments
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
 )
 
 // Config holds application configuration
 type Config struct {
-	Port      string
-	DBPath    string
-	AppName   string
-	DebugMode bool
+	Port      string `json:"port"`
+	DBPath    string `json:"db_path"`
+	AppName   string `json:"app_name"`
+	DebugMode bool   `json:"debug_mode"`
+	// ExposeStack adds stack traces to 500 responses from panics; only honored in
+	// debug mode outside EnvProd
+	ExposeStack bool `json:"expose_stack"`
+	// AppEnv is the deployment environment, one of EnvDev, EnvTest or EnvProd.
+	// It defaults to EnvProd so a deploy that forgets to set it is not seeded.
+	AppEnv string `json:"app_env"`
+	// JSONCase is JSONCaseSnake or JSONCaseCamel for response field names
+	JSONCase string `json:"json_case"`
+	// CompressMinBytes is the smallest response body that gets compressed
+	CompressMinBytes int `json:"compress_min_bytes"`
+	// LogFormat is "text" or "json"
+	LogFormat string `json:"log_format"`
+	// LogLevel is the least severe level logged: debug, info, warn or error
+	LogLevel string `json:"log_level"`
+
+	// AuthMode is AuthModeNone, AuthModeAPIKey or AuthModeJWT
+	AuthMode string `json:"auth_mode"`
+	// APIKeys lists the keys accepted in the X-API-Key header under AuthModeAPIKey
+	APIKeys []string `json:"api_keys"`
+	// AdminAPIKeys lists the keys accepted in the X-Admin-Key header on the admin
+	// routes, whatever AuthMode is; without any the admin routes are not mounted
+	AdminAPIKeys []string `json:"admin_api_keys"`
+	// RateLimitRequests caps the API requests a client IP may make per
+	// RateLimitWindow; zero turns rate limiting off
+	RateLimitRequests int `json:"rate_limit_requests"`
+	// RateLimitWindow is the period RateLimitRequests applies to
+	RateLimitWindow time.Duration `json:"rate_limit_window"`
+
+	// DBReplicaDSN optionally points read-only queries at a replica
+	DBReplicaDSN string `json:"db_replica_dsn"`
+	// DBMaxOpenConns, DBMaxIdleConns and DBConnMaxLifetime tune the connection
+	// pool of the primary and any replica; zero keeps the database/sql default
+	DBMaxOpenConns    int           `json:"db_max_open_conns"`
+	DBMaxIdleConns    int           `json:"db_max_idle_conns"`
+	DBConnMaxLifetime time.Duration `json:"db_conn_max_lifetime"`
+
+	// WelcomeBonusPoints is granted to every new user on signup
+	WelcomeBonusPoints int `json:"welcome_bonus_points"`
+	// NameCase is one of NameCasePreserve, NameCaseTitle or NameCaseUpper
+	NameCase string `json:"name_case"`
+	// MembershipIDNamespace is an optional environment code embedded in generated
+	// membership IDs (e.g. PROD gives LBK-PROD-000123); empty keeps the legacy format
+	MembershipIDNamespace string `json:"membership_id_namespace"`
+	// MembershipIDMaxAttempts caps how many candidate membership IDs are tried per user
+	MembershipIDMaxAttempts int `json:"membership_id_max_attempts"`
+	// PointsRateLimit caps the total points a user's balance may move within
+	// PointsRateWindow; zero turns the guard off
+	PointsRateLimit int `json:"points_rate_limit"`
+	// PointsRateWindow is the period PointsRateLimit applies to
+	PointsRateWindow time.Duration `json:"points_rate_window"`
+	// PointsFloors is the minimum balance deductions may leave a user with, keyed
+	// by the user's tier; tiers without a floor can go down to zero
+	PointsFloors map[string]int `json:"points_floors"`
+	// PointsFloorPolicy is PointsFloorReject or PointsFloorClamp
+	PointsFloorPolicy string `json:"points_floor_policy"`
+	// LeaderboardMinPoints is the default minimum balance to appear on the leaderboard
+	LeaderboardMinPoints int `json:"leaderboard_min_points"`
+
+	// MembershipTypes lists the tiers users can be given, lowest first. Empty keeps
+	// Bronze, Silver and Gold, which any configured list must still include.
+	MembershipTypes []string `json:"membership_types"`
+	// SilverMinPoints and GoldMinPoints are the balances at which users are moved
+	// up to Silver and Gold; zero keeps the built-in threshold
+	SilverMinPoints int `json:"silver_min_points"`
+	GoldMinPoints   int `json:"gold_min_points"`
+	// DirectTierEditsDisabled rejects manual membership type changes so tiers only follow points
+	DirectTierEditsDisabled bool `json:"direct_tier_edits_disabled"`
+	// TierTransitions lists the tiers each tier may be moved to by a manual
+	// update. An empty policy allows every transition.
+	TierTransitions map[string][]string `json:"tier_transitions"`
+	// ImmutableFields lists the user fields (by JSON name) that cannot change after creation
+	ImmutableFields []string `json:"immutable_fields"`
+	// UpdateSemantics is UpdateSemanticsMerge or UpdateSemanticsReplace
+	UpdateSemantics string `json:"update_semantics"`
+	// IdempotencyKeyTTL is how long an Idempotency-Key on POST /users replays the user it created
+	IdempotencyKeyTTL time.Duration `json:"idempotency_key_ttl"`
+	// UniqueExcludesDeleted frees a soft-deleted user's email for new and updated
+	// users (UNIQUE_INCLUDES_DELETED=false). By default deleted users keep it reserved.
+	UniqueExcludesDeleted bool `json:"unique_excludes_deleted"`
+
+	// TierReconcileInterval is how often stored tiers are checked against points; zero disables the job
+	TierReconcileInterval time.Duration `json:"tier_reconcile_interval"`
+	// TierReconcileMode is ReconcileModeReport or ReconcileModeCorrect
+	TierReconcileMode string `json:"tier_reconcile_mode"`
+
+	// DuplicateRules weighs the signals used to flag likely duplicate accounts,
+	// keyed by rule name (phone, last_name, first_name). Empty uses the defaults.
+	DuplicateRules map[string]int `json:"duplicate_rules"`
+	// DuplicateMinScore is the score at which a pair of accounts is reported
+	DuplicateMinScore int `json:"duplicate_min_score"`
+
+	// ShutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// DiskMinFreeBytes fails readiness when the filesystem holding the database
+	// file has less free space; zero only reports it
+	DiskMinFreeBytes int64 `json:"disk_min_free_bytes"`
+
+	// PIIEncryptionKey is a base64-encoded 32-byte AES key; when set, emails and
+	// phone numbers are encrypted at rest
+	PIIEncryptionKey string `json:"pii_encryption_key"`
+
+	// SMTPHost is the mail server welcome emails are sent through; empty sends none
+	SMTPHost string `json:"smtp_host"`
+	SMTPPort string `json:"smtp_port"`
+	// SMTPUsername and SMTPPassword authenticate with the mail server when set
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"smtp_password"`
+	// SMTPFrom is the sender address of welcome emails
+	SMTPFrom string `json:"smtp_from"`
+
+	// WebhookURLs receive user lifecycle events as signed JSON POSTs; empty sends none
+	WebhookURLs []string `json:"webhook_urls"`
+	// WebhookSecret keys the HMAC-SHA256 signature sent with each webhook delivery;
+	// it is required when WebhookURLs is set
+	WebhookSecret string `json:"webhook_secret"`
+	// WebhookMaxAttempts caps the tries per delivery; WebhookBackoff is the wait
+	// before the first retry, doubling on each further retry
+	WebhookMaxAttempts int           `json:"webhook_max_attempts"`
+	WebhookBackoff     time.Duration `json:"webhook_backoff"`
+
+	// ImportAllowedHosts lists the hosts CSV files may be imported from by URL
+	ImportAllowedHosts []string `json:"import_allowed_hosts"`
+	// ImportMaxBytes caps the size of a remote import file
+	ImportMaxBytes int64 `json:"import_max_bytes"`
+	// ImportURLTimeout bounds the whole download of a remote import file
+	ImportURLTimeout time.Duration `json:"import_url_timeout"`
 }
 
 // NewConfig creates a new configuration instance
 func NewConfig() *Config {
 	return &Config{
-		Port:      getEnv("PORT", "3000"),
-		DBPath:    getEnv("DB_PATH", "users.db"),
-		AppName:   getEnv("APP_NAME", "KBTG AI Backend Workshop"),
-		DebugMode: getEnv("DEBUG", "false") == "true",
+		Port:        getEnv("PORT", "3000"),
+		DBPath:      getEnv("DB_PATH", "users.db"),
+		AppName:     getEnv("APP_NAME", "KBTG AI Backend Workshop"),
+		DebugMode:   getEnv("DEBUG", "false") == "true",
+		ExposeStack: getEnv("EXPOSE_STACK", "false") == "true",
+		AppEnv:      getEnv("APP_ENV", EnvProd),
+		JSONCase:    getEnv("JSON_CASE", JSONCaseSnake),
+
+		CompressMinBytes: getEnvInt("COMPRESS_MIN_BYTES", 1024),
+		LogFormat:        getEnv("LOG_FORMAT", "text"),
+		LogLevel:         getEnv("LOG_LEVEL", "info"),
+
+		AuthMode:     getEnv("AUTH_MODE", AuthModeNone),
+		APIKeys:      getEnvList("API_KEYS"),
+		AdminAPIKeys: getEnvList("ADMIN_API_KEYS"),
+
+		RateLimitRequests: getEnvInt("RATE_LIMIT_REQUESTS", 0),
+		RateLimitWindow:   getEnvDuration("RATE_LIMIT_WINDOW", time.Minute),
+
+		DBReplicaDSN:      getEnv("DB_REPLICA_DSN", ""),
+		DBMaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 10),
+		DBMaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+		DBConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+
+		WelcomeBonusPoints: getEnvInt("WELCOME_BONUS_POINTS", 0),
+		NameCase:           getEnv("NAME_CASE", NameCasePreserve),
+
+		MembershipIDNamespace:   strings.ToUpper(getEnv("MEMBERSHIP_ID_NAMESPACE", "")),
+		MembershipIDMaxAttempts: getEnvInt("MEMBERSHIP_ID_MAX_ATTEMPTS", 5),
+		LeaderboardMinPoints:    getEnvInt("LEADERBOARD_MIN_POINTS", 0),
+
+		PointsRateLimit:  getEnvInt("POINTS_RATE_LIMIT", 0),
+		PointsRateWindow: getEnvDuration("POINTS_RATE_WINDOW", 24*time.Hour),
+
+		PointsFloors:      parseWeights(getEnv("POINTS_FLOORS", "")),
+		PointsFloorPolicy: getEnv("POINTS_FLOOR_POLICY", PointsFloorReject),
+
+		MembershipTypes:         getEnvList("MEMBERSHIP_TYPES"),
+		SilverMinPoints:         getEnvInt("SILVER_MIN_POINTS", 0),
+		GoldMinPoints:           getEnvInt("GOLD_MIN_POINTS", 0),
+		DirectTierEditsDisabled: getEnv("DISABLE_DIRECT_TIER_EDITS", "false") == "true",
+		TierTransitions:         parseTierTransitions(getEnv("TIER_TRANSITIONS", "")),
+		ImmutableFields:         getEnvList("IMMUTABLE_FIELDS"),
+		UpdateSemantics:         getEnv("UPDATE_SEMANTICS", UpdateSemanticsMerge),
+		UniqueExcludesDeleted:   getEnv("UNIQUE_INCLUDES_DELETED", "true") == "false",
+		IdempotencyKeyTTL:       getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+
+		TierReconcileInterval: getEnvDuration("TIER_RECONCILE_INTERVAL", 0),
+		TierReconcileMode:     getEnv("TIER_RECONCILE_MODE", ReconcileModeReport),
+
+		DuplicateRules:    parseWeights(getEnv("DUPLICATE_RULES", "")),
+		DuplicateMinScore: getEnvInt("DUPLICATE_MIN_SCORE", 80),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+
+		DiskMinFreeBytes: int64(getEnvInt("DISK_MIN_FREE_BYTES", 100<<20)),
+
+		PIIEncryptionKey: getEnv("PII_ENCRYPTION_KEY", ""),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+
+		WebhookURLs:        getEnvList("WEBHOOK_URLS"),
+		WebhookSecret:      getEnv("WEBHOOK_SECRET", ""),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookBackoff:     getEnvDuration("WEBHOOK_BACKOFF", time.Second),
+
+		ImportAllowedHosts: getEnvList("IMPORT_ALLOWED_HOSTS"),
+		ImportMaxBytes:     int64(getEnvInt("IMPORT_MAX_BYTES", 10<<20)),
+		ImportURLTimeout:   getEnvDuration("IMPORT_URL_TIMEOUT", 30*time.Second),
+	}
+}
+
+// Redacted returns a copy of the configuration that is safe to expose, with secrets masked.
+// Any new secret setting must be masked here.
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.DBReplicaDSN = redactDSN(c.DBReplicaDSN)
+	if c.PIIEncryptionKey != "" {
+		redacted.PIIEncryptionKey = redactedValue
+	}
+	if c.SMTPPassword != "" {
+		redacted.SMTPPassword = redactedValue
+	}
+	if c.WebhookSecret != "" {
+		redacted.WebhookSecret = redactedValue
+	}
+	redacted.APIKeys = redactKeys(c.APIKeys)
+	redacted.AdminAPIKeys = redactKeys(c.AdminAPIKeys)
+	return redacted
+}
+
+// redactKeys masks every key in keys, keeping how many there are
+func redactKeys(keys []string) []string {
+	if len(keys) == 0 {
+		return keys
+	}
+	redacted := make([]string, len(keys))
+	for i := range redacted {
+		redacted[i] = redactedValue
+	}
+	return redacted
+}
+
+// redactedValue replaces secrets in redacted output, matching url.URL.Redacted
+const redactedValue = "xxxxx"
+
+// redactDSN masks the password in URL-style (user:pass@host) and key=value (password=...) DSNs
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if key, _, found := strings.Cut(field, "="); found && strings.EqualFold(key, "password") {
+			fields[i] = key + "=" + redactedValue
+		}
 	}
+	return strings.Join(fields, " ")
+}
+
+// parseTierTransitions parses a comma-separated list of "From>To" pairs,
+// e.g. "Bronze>Silver,Silver>Gold,Gold>Silver,Silver>Bronze"
+func parseTierTransitions(value string) map[string][]string {
+	transitions := make(map[string][]string)
+	for _, pair := range strings.Split(value, ",") {
+		from, to, found := strings.Cut(pair, ">")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !found || from == "" || to == "" {
+			continue
+		}
+		transitions[from] = append(transitions[from], to)
+	}
+	return transitions
+}
+
+// parseWeights parses a comma-separated list of "name:weight" pairs,
+// e.g. "phone:50,last_name:30,first_name:20" or "Gold:1000,Silver:200"
+func parseWeights(value string) map[string]int {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		name, weight, found := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if !found || name == "" || err != nil {
+			continue
+		}
+		weights[name] = n
+	}
+	return weights
 }
 
 // getEnv gets an environment variable or returns a default value
@@ -29,3 +355,30 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a list, skipping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30s") or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
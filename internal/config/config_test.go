@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +15,7 @@ func TestNewConfig_DefaultValues(t *testing.T) {
 	os.Unsetenv("DB_PATH")
 	os.Unsetenv("APP_NAME")
 	os.Unsetenv("DEBUG")
+	os.Unsetenv("APP_ENV")
 
 	// Act
 	cfg := NewConfig()
@@ -23,6 +25,44 @@ func TestNewConfig_DefaultValues(t *testing.T) {
 	assert.Equal(t, "users.db", cfg.DBPath)
 	assert.Equal(t, "KBTG AI Backend Workshop", cfg.AppName)
 	assert.False(t, cfg.DebugMode)
+	assert.False(t, cfg.ExposeStack)
+	assert.Equal(t, EnvProd, cfg.AppEnv)
+	assert.Equal(t, JSONCaseSnake, cfg.JSONCase)
+	assert.Equal(t, 1024, cfg.CompressMinBytes)
+	assert.Equal(t, AuthModeNone, cfg.AuthMode)
+	assert.Empty(t, cfg.APIKeys)
+	assert.Empty(t, cfg.AdminAPIKeys)
+	assert.Equal(t, 0, cfg.RateLimitRequests)
+	assert.Equal(t, time.Minute, cfg.RateLimitWindow)
+	assert.Empty(t, cfg.DBReplicaDSN)
+	assert.Equal(t, 10, cfg.DBMaxOpenConns)
+	assert.Equal(t, 5, cfg.DBMaxIdleConns)
+	assert.Equal(t, 30*time.Minute, cfg.DBConnMaxLifetime)
+	assert.Equal(t, 0, cfg.WelcomeBonusPoints)
+	assert.Equal(t, NameCasePreserve, cfg.NameCase)
+	assert.Equal(t, 5, cfg.MembershipIDMaxAttempts)
+	assert.Equal(t, 0, cfg.LeaderboardMinPoints)
+	assert.Equal(t, 0, cfg.PointsRateLimit)
+	assert.Equal(t, 24*time.Hour, cfg.PointsRateWindow)
+	assert.Empty(t, cfg.PointsFloors)
+	assert.Equal(t, PointsFloorReject, cfg.PointsFloorPolicy)
+	assert.Equal(t, "text", cfg.LogFormat)
+	assert.Equal(t, "info", cfg.LogLevel)
+	assert.Empty(t, cfg.MembershipTypes)
+	assert.Equal(t, 0, cfg.SilverMinPoints)
+	assert.Equal(t, 0, cfg.GoldMinPoints)
+	assert.Empty(t, cfg.ImmutableFields)
+	assert.Equal(t, UpdateSemanticsMerge, cfg.UpdateSemantics)
+	assert.False(t, cfg.UniqueExcludesDeleted)
+	assert.Equal(t, time.Duration(0), cfg.TierReconcileInterval)
+	assert.Equal(t, ReconcileModeReport, cfg.TierReconcileMode)
+	assert.Empty(t, cfg.DuplicateRules)
+	assert.Equal(t, 80, cfg.DuplicateMinScore)
+	assert.Empty(t, cfg.ImportAllowedHosts)
+	assert.Equal(t, int64(10<<20), cfg.ImportMaxBytes)
+	assert.Equal(t, 30*time.Second, cfg.ImportURLTimeout)
+	assert.Equal(t, int64(100<<20), cfg.DiskMinFreeBytes)
+	assert.Equal(t, 10*time.Second, cfg.ShutdownTimeout)
 }
 
 func TestNewConfig_CustomValues(t *testing.T) {
@@ -31,6 +71,15 @@ func TestNewConfig_CustomValues(t *testing.T) {
 	os.Setenv("DB_PATH", "custom.db")
 	os.Setenv("APP_NAME", "Custom App")
 	os.Setenv("DEBUG", "true")
+	os.Setenv("WELCOME_BONUS_POINTS", "250")
+	os.Setenv("MEMBERSHIP_TYPES", "Bronze, Silver, Gold, Platinum")
+	os.Setenv("SILVER_MIN_POINTS", "10000")
+	os.Setenv("GOLD_MIN_POINTS", "25000")
+	os.Setenv("POINTS_FLOORS", "Gold:1000, Silver:200")
+	os.Setenv("POINTS_FLOOR_POLICY", "clamp")
+	os.Setenv("UNIQUE_INCLUDES_DELETED", "false")
+	os.Setenv("IMPORT_ALLOWED_HOSTS", "data.example.com, s3.amazonaws.com")
+	os.Setenv("IMPORT_URL_TIMEOUT", "5s")
 
 	defer func() {
 		// Cleanup
@@ -38,6 +87,15 @@ func TestNewConfig_CustomValues(t *testing.T) {
 		os.Unsetenv("DB_PATH")
 		os.Unsetenv("APP_NAME")
 		os.Unsetenv("DEBUG")
+		os.Unsetenv("WELCOME_BONUS_POINTS")
+		os.Unsetenv("MEMBERSHIP_TYPES")
+		os.Unsetenv("SILVER_MIN_POINTS")
+		os.Unsetenv("GOLD_MIN_POINTS")
+		os.Unsetenv("POINTS_FLOORS")
+		os.Unsetenv("POINTS_FLOOR_POLICY")
+		os.Unsetenv("UNIQUE_INCLUDES_DELETED")
+		os.Unsetenv("IMPORT_ALLOWED_HOSTS")
+		os.Unsetenv("IMPORT_URL_TIMEOUT")
 	}()
 
 	// Act
@@ -48,6 +106,110 @@ func TestNewConfig_CustomValues(t *testing.T) {
 	assert.Equal(t, "custom.db", cfg.DBPath)
 	assert.Equal(t, "Custom App", cfg.AppName)
 	assert.True(t, cfg.DebugMode)
+	assert.Equal(t, 250, cfg.WelcomeBonusPoints)
+	assert.Equal(t, []string{"Bronze", "Silver", "Gold", "Platinum"}, cfg.MembershipTypes)
+	assert.Equal(t, 10000, cfg.SilverMinPoints)
+	assert.Equal(t, 25000, cfg.GoldMinPoints)
+	assert.Equal(t, map[string]int{"Gold": 1000, "Silver": 200}, cfg.PointsFloors)
+	assert.Equal(t, PointsFloorClamp, cfg.PointsFloorPolicy)
+	assert.True(t, cfg.UniqueExcludesDeleted)
+	assert.Equal(t, []string{"data.example.com", "s3.amazonaws.com"}, cfg.ImportAllowedHosts)
+	assert.Equal(t, 5*time.Second, cfg.ImportURLTimeout)
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	tests := []struct {
+		name     string
+		dsn      string
+		expected string
+	}{
+		{"url with password", "postgres://app:s3cret@db:5432/users?sslmode=disable", "postgres://app:xxxxx@db:5432/users?sslmode=disable"},
+		{"key value with password", "host=db user=app password=s3cret dbname=users", "host=db user=app password=xxxxx dbname=users"},
+		{"file path", "replica.db", "replica.db"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Port: "3000", DBReplicaDSN: tt.dsn}
+
+			redacted := cfg.Redacted()
+
+			assert.Equal(t, tt.expected, redacted.DBReplicaDSN)
+			assert.Equal(t, "3000", redacted.Port)
+			assert.Equal(t, tt.dsn, cfg.DBReplicaDSN) // original untouched
+		})
+	}
+}
+
+func TestConfig_Redacted_PIIEncryptionKey(t *testing.T) {
+	// Arrange
+	cfg := &Config{PIIEncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}
+
+	// Act
+	redacted := cfg.Redacted()
+
+	// Assert
+	assert.Equal(t, "xxxxx", redacted.PIIEncryptionKey)
+	assert.Empty(t, (&Config{}).Redacted().PIIEncryptionKey)
+}
+
+func TestConfig_Redacted_SMTPPassword(t *testing.T) {
+	// Arrange
+	cfg := &Config{SMTPHost: "mail.example.com", SMTPPassword: "s3cret"}
+
+	// Act
+	redacted := cfg.Redacted()
+
+	// Assert
+	assert.Equal(t, "xxxxx", redacted.SMTPPassword)
+	assert.Equal(t, "mail.example.com", redacted.SMTPHost)
+}
+
+func TestConfig_Redacted_WebhookSecret(t *testing.T) {
+	// Arrange
+	cfg := &Config{WebhookURLs: []string{"https://hooks.example.com/users"}, WebhookSecret: "s3cret"}
+
+	// Act
+	redacted := cfg.Redacted()
+
+	// Assert
+	assert.Equal(t, "xxxxx", redacted.WebhookSecret)
+	assert.Equal(t, cfg.WebhookURLs, redacted.WebhookURLs)
+}
+
+func TestConfig_Redacted_APIKeys(t *testing.T) {
+	// Arrange
+	cfg := &Config{APIKeys: []string{"key-one", "key-two"}, AdminAPIKeys: []string{"admin-key"}}
+
+	// Act
+	redacted := cfg.Redacted()
+
+	// Assert
+	assert.Equal(t, []string{"xxxxx", "xxxxx"}, redacted.APIKeys)
+	assert.Equal(t, []string{"xxxxx"}, redacted.AdminAPIKeys)
+	assert.Equal(t, []string{"key-one", "key-two"}, cfg.APIKeys) // original untouched
+}
+
+func TestParseTierTransitions(t *testing.T) {
+	// Act
+	transitions := parseTierTransitions("Bronze>Silver, Silver>Gold,Silver>Bronze,invalid,>Gold")
+
+	// Assert
+	assert.Equal(t, map[string][]string{
+		"Bronze": {"Silver"},
+		"Silver": {"Gold", "Bronze"},
+	}, transitions)
+	assert.Empty(t, parseTierTransitions(""))
+}
+
+func TestParseWeights(t *testing.T) {
+	// Act
+	weights := parseWeights("phone:50, last_name : 30,invalid,first_name:x,:10")
+
+	// Assert
+	assert.Equal(t, map[string]int{"phone": 50, "last_name": 30}, weights)
+	assert.Empty(t, parseWeights(""))
 }
 
 func TestGetEnv(t *testing.T) {
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrValidation is wrapped by ValidationError when a request fails its validate tags
+	ErrValidation = errors.New("validation failed")
+	// ErrUserNotFound is returned when no user, or no user that is not deleted, has the given ID or key
+	ErrUserNotFound = errors.New("user not found")
+	// ErrCouldNotGenerateID is returned when no unused membership ID was found within the retry limit
+	ErrCouldNotGenerateID = errors.New("could not generate a unique membership ID")
+	// ErrInvalidMembershipType is returned for a membership type outside the configured tiers
+	ErrInvalidMembershipType = errors.New("invalid membership type")
+	// ErrDirectTierEditDenied is returned when tiers may only change through points
+	ErrDirectTierEditDenied = errors.New("membership type can only change through points")
+	// ErrTierTransitionNotAllowed is returned when a manual tier change is not permitted by policy
+	ErrTierTransitionNotAllowed = errors.New("membership type transition not allowed")
+	// ErrUnsupportedAuthMode is returned at startup for an auth mode that cannot be enforced
+	ErrUnsupportedAuthMode = errors.New("unsupported auth mode")
+	// ErrInvalidMembershipID is returned when looking up a user by a blank membership ID
+	ErrInvalidMembershipID = errors.New("invalid membership ID")
+	// ErrMembershipIDRevoked is returned when verifying a membership ID from a card that was reissued
+	ErrMembershipIDRevoked = errors.New("membership card has been revoked")
+	// ErrEmailTaken is returned when creating or updating a user with an email another user holds
+	ErrEmailTaken = errors.New("user with this email already exists")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotentUserDeleted is returned when replaying an idempotency key whose user has since been deleted
+	ErrIdempotentUserDeleted = errors.New("user created with this idempotency key has been deleted")
+	// ErrInvalidStatus is returned for an account status other than active or suspended
+	ErrInvalidStatus = errors.New("invalid status")
+	// ErrUserSuspended is returned when changing the points of a suspended user
+	ErrUserSuspended = errors.New("user is suspended")
+	// ErrUserModified is returned when a conditional write finds the user changed since the given revision
+	ErrUserModified = errors.New("user has been modified")
+	// ErrRestoreConflict is returned when a deleted user's email has since been taken by another user
+	ErrRestoreConflict = errors.New("email is in use by another user")
+	// ErrFieldImmutable is returned when an update changes a field configured as immutable
+	ErrFieldImmutable = errors.New("field cannot be changed")
+	// ErrNegativePoints is returned when a points balance would be set below zero
+	ErrNegativePoints = errors.New("points must not be negative")
+	// ErrPointsRateExceeded is returned when an adjustment would move a balance by more than the configured limit
+	ErrPointsRateExceeded = errors.New("points change limit exceeded")
+	// ErrBelowPointsFloor is returned when a deduction would take a balance below its tier's floor
+	ErrBelowPointsFloor = errors.New("points must not drop below the tier floor")
+	// ErrInvalidSort is returned for a sort field or order that users cannot be listed by
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrEmptySearchQuery is returned when a user search has no search text
+	ErrEmptySearchQuery = errors.New("search query is required")
+	// ErrUnknownComputedField is returned when a computed field outside domain.ComputedFields is requested
+	ErrUnknownComputedField = errors.New("unknown computed field")
+	// ErrInvalidThresholds is returned for tier thresholds that are not positive and increasing
+	ErrInvalidThresholds = errors.New("invalid tier thresholds")
+	// ErrInvalidBatch is returned when a batch request is empty or exceeds its size limit
+	ErrInvalidBatch = errors.New("invalid batch")
+	// ErrInvalidImport is returned when an import file is not a usable CSV
+	ErrInvalidImport = errors.New("invalid import file")
+	// ErrInvalidImportMode is returned for an import mode other than atomic or partial
+	ErrInvalidImportMode = errors.New("invalid import mode")
+	// ErrImportRejected is returned when an atomic import has invalid rows and nothing was applied
+	ErrImportRejected = errors.New("import rejected, no rows were applied")
+	// ErrImportURLNotAllowed is returned when an import URL is malformed or its host is not allowlisted
+	ErrImportURLNotAllowed = errors.New("import URL is not allowed")
+	// ErrImportFetchFailed is returned when a remote import file cannot be downloaded
+	ErrImportFetchFailed = errors.New("failed to fetch import file")
+	// ErrImportTooLarge is returned when an import file exceeds the size limit
+	ErrImportTooLarge = errors.New("import file exceeds the size limit")
+)
+
+// FieldError describes why a single request field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of a request that failed validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Unwrap lets errors.Is match ErrValidation
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// User lifecycle event types
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+)
+
+// UserEvent reports a change to a user that has been stored
+type UserEvent struct {
+	// ID is unique per event, so receivers can drop redelivered events
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	UserID     uint      `json:"user_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// EventPublisher delivers user events to whoever has subscribed to them
+type EventPublisher interface {
+	Publish(event UserEvent)
+}
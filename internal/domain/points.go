@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Minimum points balance for each tier when the tier is derived from points
+const (
+	SilverMinPoints = 5000
+	GoldMinPoints   = 10000
+)
+
+// MembershipTypes lists the built-in membership tiers, lowest first. Tiers
+// derived from points are always one of these.
+var MembershipTypes = []string{"Bronze", "Silver", "Gold"}
+
+// CheckMembershipTypes rejects a configured tier list that leaves out a
+// built-in tier. An empty list keeps the built-in tiers.
+func CheckMembershipTypes(types []string) error {
+	if len(types) == 0 {
+		return nil
+	}
+	for _, builtIn := range MembershipTypes {
+		if TierRank(types, builtIn) < 0 {
+			return fmt.Errorf("%w: %s is required", ErrInvalidMembershipType, builtIn)
+		}
+	}
+	return nil
+}
+
+// TierRank returns the position of t in types, which are ordered lowest first,
+// or -1 for an unknown tier
+func TierRank(types []string, t string) int {
+	for i, membershipType := range types {
+		if membershipType == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// TierThresholds holds the minimum points balance for each tier above Bronze
+type TierThresholds struct {
+	SilverMinPoints int `json:"silver_min_points"`
+	GoldMinPoints   int `json:"gold_min_points"`
+}
+
+// DefaultTierThresholds are the thresholds tiers are derived with unless configured otherwise
+var DefaultTierThresholds = TierThresholds{SilverMinPoints: SilverMinPoints, GoldMinPoints: GoldMinPoints}
+
+// TierFor returns the membership type a points balance qualifies for under these thresholds
+func (t TierThresholds) TierFor(points int) string {
+	switch {
+	case points >= t.GoldMinPoints:
+		return "Gold"
+	case points >= t.SilverMinPoints:
+		return "Silver"
+	default:
+		return "Bronze"
+	}
+}
+
+// PointsToNextTier returns how many more points a balance needs to reach the
+// next tier under these thresholds, or 0 at the top tier
+func (t TierThresholds) PointsToNextTier(points int) int {
+	switch {
+	case points >= t.GoldMinPoints:
+		return 0
+	case points >= t.SilverMinPoints:
+		return t.GoldMinPoints - points
+	default:
+		return t.SilverMinPoints - points
+	}
+}
+
+// TierPolicy decides the tier a points change leaves a user in. Users move up to
+// the tier their balance qualifies for under Thresholds, and only move down when
+// AllowDowngrade is set. Users in a configured tier outside MembershipTypes, such
+// as Platinum, keep it.
+type TierPolicy struct {
+	Thresholds     TierThresholds
+	AllowDowngrade bool
+	// Fixed, when set, is the tier every user moves to whatever their balance
+	Fixed string
+}
+
+// TierAfter returns the tier a user in current ends up in with a balance of points
+func (p TierPolicy) TierAfter(current string, points int) string {
+	if p.Fixed != "" {
+		return p.Fixed
+	}
+	if TierRank(MembershipTypes, current) < 0 {
+		return current
+	}
+	earned := p.Thresholds.TierFor(points)
+	if p.AllowDowngrade || TierRank(MembershipTypes, earned) > TierRank(MembershipTypes, current) {
+		return earned
+	}
+	return current
+}
+
+// TierMove counts the users that would go from one tier to another
+type TierMove struct {
+	From  string
+	To    string
+	Count int64
+}
+
+// TierSimulation compares a tier's current size with its size under proposed thresholds
+type TierSimulation struct {
+	MembershipType string `json:"membership_type"`
+	CurrentCount   int64  `json:"current_count"`
+	SimulatedCount int64  `json:"simulated_count"`
+	MovingIn       int64  `json:"moving_in"`
+	MovingOut      int64  `json:"moving_out"`
+}
+
+// Reasons recorded on points transactions
+const (
+	PointsReasonInitial      = "initial"
+	PointsReasonWelcomeBonus = "welcome_bonus"
+	PointsReasonUpdate       = "update"
+	PointsReasonSet          = "set"
+	PointsReasonAdd          = "add"
+	PointsReasonReset        = "reset"
+)
+
+// PointsCSVColumns is the header row of a bulk points import; reason is optional
+var PointsCSVColumns = []string{"membership_id", "delta", "reason"}
+
+// PointsReasonImport is recorded for imported rows that carry no reason
+const PointsReasonImport = "import"
+
+// Bulk points import modes. An atomic import applies every row or none; a
+// partial import applies the rows that are valid.
+const (
+	ImportModeAtomic  = "atomic"
+	ImportModePartial = "partial"
+)
+
+// PointsAdjustment moves a user's points balance by Delta
+type PointsAdjustment struct {
+	UserID uint
+	Delta  int
+	Reason string
+}
+
+// PointsGuard holds the limits the repository enforces on a balance change in
+// the same transaction that writes it, so concurrent changes cannot slip past them
+type PointsGuard struct {
+	// Floors is the minimum balance a deduction may leave, keyed by tier
+	Floors map[string]int
+	// ClampToFloor stops a deduction at the floor instead of rejecting it
+	ClampToFloor bool
+	// RateLimit caps the total points a balance may move, in either direction,
+	// within RateWindow; zero turns the check off
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// Apply checks moving balance, held in tier, by delta against the tier's floor
+// and returns the delta to apply. A deduction past the floor is reduced to stop
+// at it under ClampToFloor and rejected with ErrBelowPointsFloor otherwise.
+func (g PointsGuard) Apply(tier string, balance, delta int) (int, error) {
+	floor, ok := g.Floors[tier]
+	if !ok || delta >= 0 || balance+delta >= floor {
+		return delta, nil
+	}
+	if g.ClampToFloor {
+		return min(0, floor-balance), nil
+	}
+	return delta, fmt.Errorf("%w: %s balance cannot go below %d", ErrBelowPointsFloor, tier, floor)
+}
+
+// MinBalance returns the lowest balance moving a user in tier by delta may
+// leave: the tier's floor for a deduction and zero otherwise
+func (g PointsGuard) MinBalance(tier string, delta int) int {
+	if floor := g.Floors[tier]; delta < 0 && floor > 0 {
+		return floor
+	}
+	return 0
+}
+
+// CheckRate rejects moving a balance by delta when moved, the total the balance
+// has already moved within RateWindow, would then exceed RateLimit
+func (g PointsGuard) CheckRate(moved int64, delta int) error {
+	if g.RateLimit <= 0 {
+		return nil
+	}
+	if delta < 0 {
+		delta = -delta
+	}
+	if moved+int64(delta) > int64(g.RateLimit) {
+		return fmt.Errorf("%w: at most %d points per %s", ErrPointsRateExceeded, g.RateLimit, g.RateWindow)
+	}
+	return nil
+}
+
+// PointsTransaction records a change to a user's points balance
+type PointsTransaction struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	UserID    uint      `json:"user_id" gorm:"index;not null"`
+	Delta     int       `json:"delta"`
+	Balance   int       `json:"balance"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PointsTransactionRepository defines the repository interface for the points ledger
+type PointsTransactionRepository interface {
+	ListByUser(userID uint) ([]PointsTransaction, error)
+}
+
+// PointsUseCase defines the use case interface for the points ledger
+type PointsUseCase interface {
+	GetHistory(userID uint) ([]PointsTransaction, error)
+}
+
+// AddPointsRequest represents the request to move a user's points by a relative amount
+type AddPointsRequest struct {
+	Delta *int `json:"delta"`
+}
+
+// SetPointsRequest represents the request to set a user's points to an absolute value
+type SetPointsRequest struct {
+	Points *int `json:"points"`
+}
+
+// Triggers for tier changes not caused by a points transaction; other changes
+// carry the reason of the points transaction that caused them
+const (
+	TierTriggerUpdate    = "update"
+	TierTriggerReconcile = "reconcile"
+)
+
+// TierMismatch describes a user whose stored tier differs from what their points imply
+type TierMismatch struct {
+	UserID    uint   `json:"user_id"`
+	Points    int    `json:"points"`
+	Stored    string `json:"stored"`
+	Expected  string `json:"expected"`
+	Corrected bool   `json:"corrected"`
+}
+
+// TierChange records a user moving from one membership tier to another
+type TierChange struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	UserID    uint      `json:"user_id" gorm:"index;not null"`
+	From      string    `json:"from" gorm:"column:from_tier"`
+	To        string    `json:"to" gorm:"column:to_tier"`
+	Trigger   string    `json:"trigger" gorm:"column:change_trigger"`
+	CreatedAt time.Time `json:"at"`
+}
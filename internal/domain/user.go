@@ -1,20 +1,61 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User represents a user entity in the domain
 type User struct {
 	ID             uint      `json:"id" gorm:"primarykey"`
 	FirstName      string    `json:"first_name" gorm:"not null"`
 	LastName       string    `json:"last_name" gorm:"not null"`
-	Email          string    `json:"email" gorm:"unique;not null"`
-	Phone          string    `json:"phone"`
+	Email          string    `json:"email" gorm:"serializer:pii;uniqueIndex:idx_users_email,where:deleted_at IS NULL;not null"`
+	Phone          string    `json:"phone" gorm:"serializer:pii"`
 	MembershipType string    `json:"membership_type" gorm:"default:'Bronze'"` // Bronze, Silver, Gold
 	MembershipID   string    `json:"membership_id" gorm:"unique"`
 	JoinDate       time.Time `json:"join_date" gorm:"autoCreateTime"`
 	Points         int       `json:"points" gorm:"default:0"`
+	Status         string    `json:"status" gorm:"default:'active';index"` // active, suspended
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	// DeletedAt marks a soft-deleted user; deleted users are hidden from queries
+	// and their email may be registered again
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	// EmailHash is a keyed hash of the email used to look up users while PII
+	// encryption is on, as the encrypted email column cannot be compared; empty otherwise.
+	// Its unique index stands in for idx_users_email, which cannot catch
+	// duplicates once the email is stored as randomized ciphertext.
+	EmailHash string `json:"-" gorm:"uniqueIndex:idx_users_email_hash,where:deleted_at IS NULL AND email_hash <> ''"`
+}
+
+// Account statuses a user can be in
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+)
+
+// UserStatuses lists the valid account statuses
+var UserStatuses = []string{UserStatusActive, UserStatusSuspended}
+
+// ETag returns an entity tag identifying the current revision of the user
+func (u *User) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, u.ID, u.UpdatedAt.UnixNano())
+}
+
+// ParseETag returns the user ID and UpdatedAt of the revision an ETag from
+// User.ETag identifies, and false for any other value
+func ParseETag(etag string) (uint, time.Time, bool) {
+	var id uint
+	var nanos int64
+	if _, err := fmt.Sscanf(etag, `"%d-%d"`, &id, &nanos); err != nil {
+		return 0, time.Time{}, false
+	}
+	return id, time.Unix(0, nanos), true
 }
 
 // CreateUserRequest represents the request to create a new user
@@ -22,36 +63,317 @@ type CreateUserRequest struct {
 	FirstName      string `json:"first_name" validate:"required"`
 	LastName       string `json:"last_name" validate:"required"`
 	Email          string `json:"email" validate:"required,email"`
-	Phone          string `json:"phone"`
+	Phone          string `json:"phone" validate:"omitempty,phone"`
 	MembershipType string `json:"membership_type"`
-	Points         int    `json:"points"`
+	Points         int    `json:"points" validate:"nonnegative"`
 }
 
-// UpdateUserRequest represents the request to update a user
+// UpdateUserRequest represents the request to update a user. Phone and Points are
+// pointers so that an omitted field is left unchanged while "" or 0 clears it.
+// A points change only moves the user down a tier when AllowDowngrade is set.
 type UpdateUserRequest struct {
-	FirstName      string `json:"first_name,omitempty"`
-	LastName       string `json:"last_name,omitempty"`
-	Email          string `json:"email,omitempty" validate:"omitempty,email"`
-	Phone          string `json:"phone,omitempty"`
-	MembershipType string `json:"membership_type,omitempty"`
-	Points         int    `json:"points,omitempty"`
+	FirstName      string  `json:"first_name,omitempty"`
+	LastName       string  `json:"last_name,omitempty"`
+	Email          string  `json:"email,omitempty" validate:"omitempty,email"`
+	Phone          *string `json:"phone,omitempty" validate:"omitempty,phone"`
+	MembershipType string  `json:"membership_type,omitempty"`
+	Points         *int    `json:"points,omitempty" validate:"omitempty,nonnegative"`
+	AllowDowngrade bool    `json:"allow_downgrade,omitempty"`
+}
+
+// EmailsExistRequest represents the request to check which emails are already registered
+type EmailsExistRequest struct {
+	Emails []string `json:"emails"`
+}
+
+// MembershipIDsRequest represents the request to fetch users by their membership IDs
+type MembershipIDsRequest struct {
+	MembershipIDs []string `json:"membership_ids"`
+}
+
+// NormalizeRequest represents a batch of emails and phone numbers to clean up
+// without looking anything up
+type NormalizeRequest struct {
+	Emails []string `json:"emails"`
+	Phones []string `json:"phones"`
+}
+
+// NormalizedValue reports the canonical form of one input and whether it is valid
+type NormalizedValue struct {
+	Input      string `json:"input"`
+	Normalized string `json:"normalized"`
+	Valid      bool   `json:"valid"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NormalizeResult holds the normalized emails and phone numbers in request order
+type NormalizeResult struct {
+	Emails []NormalizedValue `json:"emails"`
+	Phones []NormalizedValue `json:"phones"`
+}
+
+// Sort orders accepted when listing users
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// UserListOptions controls how users are listed. A zero Limit returns every user;
+// an empty SortBy orders by id ascending; empty MembershipTypes applies no filter.
+// Soft-deleted users are only listed with IncludeDeleted. A non-zero AfterID
+// lists only users with a greater id, for cursor pagination. A non-empty Status
+// lists only users in that account status.
+type UserListOptions struct {
+	Page            int
+	Limit           int
+	AfterID         uint
+	SortBy          string
+	Order           string
+	MembershipTypes []string
+	Status          string
+	IncludeDeleted  bool
+}
+
+// BatchDeleteRequest represents the request to delete several users at once
+type BatchDeleteRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+// Batch delete result statuses
+const (
+	BatchDeleteDeleted  = "deleted"
+	BatchDeleteNotFound = "not_found"
+)
+
+// BatchDeleteResult reports the outcome of deleting a single user in a batch
+type BatchDeleteResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+}
+
+// DuplicateCandidate is a pair of accounts that likely belong to the same person
+type DuplicateCandidate struct {
+	UserID      uint     `json:"user_id"`
+	OtherUserID uint     `json:"other_user_id"`
+	Score       int      `json:"score"`
+	Matched     []string `json:"matched"`
+}
+
+// TierStats aggregates the users of one membership tier
+type TierStats struct {
+	MembershipType string  `json:"membership_type"`
+	Count          int64   `json:"count"`
+	TotalPoints    int64   `json:"total_points"`
+	AveragePoints  float64 `json:"average_points"`
+}
+
+// UserStats summarises all users, or the users of one membership tier
+type UserStats struct {
+	TotalUsers    int64            `json:"total_users"`
+	TierCounts    map[string]int64 `json:"tier_counts"`
+	TotalPoints   int64            `json:"total_points"`
+	AveragePoints float64          `json:"average_points"`
+	Tiers         []TierStats      `json:"tiers"`
+}
+
+// TierStatsCSVColumns is the header row of the stats CSV export
+var TierStatsCSVColumns = []string{"membership_type", "count", "total_points", "average_points"}
+
+// Columns of a user CSV import
+const (
+	UserCSVFirstName      = "first_name"
+	UserCSVLastName       = "last_name"
+	UserCSVEmail          = "email"
+	UserCSVPhone          = "phone"
+	UserCSVMembershipType = "membership_type"
+	UserCSVPoints         = "points"
+)
+
+// UserCSVColumns is the header row used by CSV import and its template
+var UserCSVColumns = []string{UserCSVFirstName, UserCSVLastName, UserCSVEmail, UserCSVPhone, UserCSVMembershipType, UserCSVPoints}
+
+// UserCSVRequiredColumns are the UserCSVColumns an import header must include
+var UserCSVRequiredColumns = []string{UserCSVFirstName, UserCSVLastName, UserCSVEmail}
+
+// ImportURLRequest represents the request to import users from a remote CSV
+type ImportURLRequest struct {
+	URL string `json:"url"`
+}
+
+// Import row statuses
+const (
+	ImportRowCreated = "created"
+	ImportRowApplied = "applied"
+	ImportRowSkipped = "skipped"
+	ImportRowError   = "error"
+)
+
+// ImportRowResult reports the outcome of importing a single CSV row
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	UserID uint   `json:"user_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// LeaderboardOptions controls which users are ranked on the leaderboard.
+// A nil MinPoints falls back to the configured default.
+type LeaderboardOptions struct {
+	MembershipType string
+	Limit          int
+	MinPoints      *int
+}
+
+// LeaderboardEntry represents a ranked user on the points leaderboard
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	User
+}
+
+// Dashboard gathers the headline metrics shown on the ops dashboard
+type Dashboard struct {
+	TotalUsers   int64              `json:"total_users"`
+	TierCounts   map[string]int64   `json:"tier_counts"`
+	NewThisMonth int64              `json:"new_this_month"`
+	TotalPoints  int64              `json:"total_points"`
+	TopUsers     []LeaderboardEntry `json:"top_users"`
+}
+
+// Computed fields that can be requested alongside a user
+const (
+	ComputedMembershipDays      = "membership_days"
+	ComputedPointsToNextTier    = "points_to_next_tier"
+	ComputedProfileCompleteness = "profile_completeness"
+	ComputedRank                = "rank"
+)
+
+// ComputedFields lists the computed fields a client may request
+var ComputedFields = []string{ComputedMembershipDays, ComputedPointsToNextTier, ComputedProfileCompleteness, ComputedRank}
+
+// UserChange is a user returned by the change feed, flagged when soft-deleted
+type UserChange struct {
+	User
+	Deleted bool `json:"deleted"`
+}
+
+// ChangedAt returns when the user was last updated or deleted
+func (u *User) ChangedAt() time.Time {
+	if u.DeletedAt.Valid && u.DeletedAt.Time.After(u.UpdatedAt) {
+		return u.DeletedAt.Time
+	}
+	return u.UpdatedAt
+}
+
+// MembershipIDChange records a membership ID that was reissued
+type MembershipIDChange struct {
+	UserID uint   `json:"user_id"`
+	OldID  string `json:"old_membership_id"`
+	NewID  string `json:"new_membership_id"`
+}
+
+// RevokedMembershipID records a membership ID that was replaced on a lost card
+// and must no longer verify
+type RevokedMembershipID struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	MembershipID string    `json:"membership_id" gorm:"index"`
+	UserID       uint      `json:"user_id"`
+	CreatedAt    time.Time `json:"revoked_at"`
+}
+
+// IdempotencyKey records the user created by a POST /users request sent with an
+// Idempotency-Key header, so a retry returns that user instead of creating another
+type IdempotencyKey struct {
+	Key string `gorm:"column:idempotency_key;primaryKey"`
+	// RequestHash fingerprints the request body so a key reused for a different user is rejected
+	RequestHash string
+	UserID      uint
+	CreatedAt   time.Time `gorm:"index"`
 }
 
 // UserRepository defines the repository interface for user operations
 type UserRepository interface {
 	GetAll() ([]User, error)
+	List(opts UserListOptions) ([]User, int64, error)
+	Search(query string, opts UserListOptions) ([]User, int64, error)
+	ListChangedSince(since time.Time) ([]User, error)
 	GetByID(id uint) (*User, error)
+	GetByIDFromPrimary(id uint) (*User, error)
 	GetByEmail(email string) (*User, error)
-	Create(user *User) error
-	Update(user *User) error
+	GetByMembershipID(membershipID string) (*User, error)
+	GetByMembershipIDs(membershipIDs []string) ([]User, error)
+	FindExistingEmails(emails []string, includeDeleted bool) ([]string, error)
+	EmailInUse(email string, includeDeleted bool) (bool, error)
+	Create(user *User, welcomeBonus int) error
+	Update(user *User, guard PointsGuard) error
+	UpdateMembershipID(id uint, membershipID string) error
+	ReplaceMembershipID(id uint, membershipID string) (*MembershipIDChange, error)
+	IsMembershipIDRevoked(membershipID string) (bool, error)
+	MembershipIDInUse(membershipID string) (bool, error)
+	SetPoints(id uint, points int, tiers TierPolicy, reason string, guard PointsGuard) (*User, *PointsTransaction, error)
+	AddPoints(id uint, delta int, tiers TierPolicy, guard PointsGuard) error
+	SetMembershipType(id uint, membershipType, trigger string) error
+	UpdateStatus(id uint, status string) error
+	AdjustPoints(adjustments []PointsAdjustment, tiers TierPolicy, guard PointsGuard) error
+	GetTierHistory(userID uint) ([]TierChange, error)
+	SumPointsChange(userID uint, since time.Time) (int64, error)
 	Delete(id uint) error
+	DeleteIfUnmodified(id uint, revisions []time.Time) error
+	Restore(id uint) error
+	DeleteByIDs(ids []uint) ([]uint, error)
+	SumPoints(membershipType string) (int64, error)
+	GetTierStats(membershipType string) ([]TierStats, error)
+	GetUserStats(membershipType string) (*UserStats, error)
+	GetIdempotencyKey(key string, since time.Time) (*IdempotencyKey, error)
+	CreateWithIdempotencyKey(user *User, welcomeBonus int, key *IdempotencyKey, expiredBefore time.Time) error
+	CountTierMoves(thresholds TierThresholds) ([]TierMove, error)
+	GetTopByPoints(opts LeaderboardOptions) ([]User, error)
+	CountWithMorePoints(points int) (int64, error)
+	CountJoinedSince(since time.Time) (int64, error)
+	// WithTransaction runs fn with a repository whose operations commit together
+	// when fn returns nil, or all roll back when it returns an error
+	WithTransaction(fn func(txRepo UserRepository) error) error
+}
+
+// Notifier sends messages to users about their accounts
+type Notifier interface {
+	SendWelcome(user *User) error
 }
 
 // UserUseCase defines the use case interface for user operations
 type UserUseCase interface {
 	GetAllUsers() ([]User, error)
+	ListUsers(opts UserListOptions) ([]User, int64, error)
+	SearchUsers(query string, opts UserListOptions) ([]User, int64, error)
+	GetUserChanges(since time.Time) ([]UserChange, time.Time, error)
 	GetUserByID(id uint) (*User, error)
+	GetUserByMembershipID(membershipID string) (*User, error)
+	GetUsersByMembershipIDs(membershipIDs []string) ([]User, []string, error)
 	CreateUser(req CreateUserRequest) (*User, error)
+	CreateUserIdempotent(key string, req CreateUserRequest) (*User, bool, error)
 	UpdateUser(id uint, req UpdateUserRequest) (*User, error)
+	SetPoints(id uint, points int) (*User, *PointsTransaction, error)
+	AddPoints(id uint, delta int) (*User, error)
+	ResetUser(id uint) (*User, *PointsTransaction, error)
+	GetTierHistory(id uint) ([]TierChange, error)
 	DeleteUser(id uint) error
+	DeleteUserIfUnmodified(id uint, revisions []time.Time) error
+	RestoreUser(id uint) (*User, error)
+	UpdateStatus(id uint, status string) (*User, error)
+	DeleteUsers(ids []uint) ([]BatchDeleteResult, error)
+	GetTotalPoints(membershipType string) (int64, error)
+	GetTierStats(membershipType string) ([]TierStats, error)
+	GetUserStats(membershipType string) (*UserStats, error)
+	GetLeaderboard(opts LeaderboardOptions) ([]LeaderboardEntry, error)
+	GetDashboard() (*Dashboard, error)
+	ReissueInvalidMembershipIDs() ([]MembershipIDChange, error)
+	ReissueMembershipID(id uint) (*MembershipIDChange, error)
+	VerifyMembershipID(membershipID string) (*User, error)
+	ComputeFields(user *User, fields []string) (map[string]int, error)
+	ReconcileTiers() ([]TierMismatch, error)
+	SimulateTiers(thresholds TierThresholds) ([]TierSimulation, error)
+	ImportUsersFromURL(ctx context.Context, rawURL string) ([]ImportRowResult, error)
+	ImportPoints(r io.Reader, mode string) ([]ImportRowResult, error)
+	CheckEmailsExist(emails []string) (map[string]bool, error)
+	NormalizeContacts(req NormalizeRequest) (*NormalizeResult, error)
+	FindDuplicateCandidates() ([]DuplicateCandidate, error)
 }
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// AdminHandler handles HTTP requests for operational endpoints
+type AdminHandler struct {
+	userUseCase domain.UserUseCase
+	config      *config.Config
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(userUseCase domain.UserUseCase, cfg *config.Config) *AdminHandler {
+	return &AdminHandler{
+		userUseCase: userUseCase,
+		config:      cfg,
+	}
+}
+
+// GetConfig handles GET /admin/config
+func (h *AdminHandler) GetConfig(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"data": h.config.Redacted(),
+	})
+}
+
+// ReissueMembershipIDs handles POST /admin/membership-ids/reissue
+func (h *AdminHandler) ReissueMembershipIDs(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	changes, err := h.userUseCase.ReissueInvalidMembershipIDs()
+	stop()
+	if err != nil {
+		requestLogger(c).Error("Failed to reissue membership IDs", "error", err)
+		body := codedErrorBody(codeInternal, "Failed to reissue membership IDs")
+		body["data"] = changes
+		return c.Status(500).JSON(body)
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  changes,
+		"count": len(changes),
+	})
+}
+
+// GetDuplicateCandidates handles GET /admin/users/duplicates
+func (h *AdminHandler) GetDuplicateCandidates(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	candidates, err := h.userUseCase.FindDuplicateCandidates()
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to find duplicate users")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  candidates,
+		"count": len(candidates),
+	})
+}
+
+// GetDashboard handles GET /admin/dashboard
+func (h *AdminHandler) GetDashboard(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	dashboard, err := h.userUseCase.GetDashboard()
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to build dashboard")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": dashboard,
+	})
+}
+
+// SimulateTiers handles POST /admin/tiers/simulate
+func (h *AdminHandler) SimulateTiers(c *fiber.Ctx) error {
+	var thresholds domain.TierThresholds
+	if err := parseBody(c, &thresholds); err != nil {
+		return sendError(c, 400, err)
+	}
+
+	stop := trackDB(c)
+	simulations, err := h.userUseCase.SimulateTiers(thresholds)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidThresholds) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to simulate tiers")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": simulations,
+	})
+}
+
+// ResetUser handles POST /admin/users/:id/reset?confirm=true
+func (h *AdminHandler) ResetUser(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	if c.Query("confirm") != "true" {
+		return sendErrorCode(c, 400, codeConfirmRequired, "Resetting a user requires confirm=true")
+	}
+
+	stop := trackDB(c)
+	user, txn, err := h.userUseCase.ResetUser(uint(id))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		if errors.Is(err, domain.ErrUserSuspended) {
+			return sendError(c, 409, err)
+		}
+		return internalError(c, err, "Failed to reset user")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        user,
+		"transaction": txn,
+	})
+}
+
+// RegisterRoutes mounts the admin endpoints on the given router behind the
+// X-Admin-Key header. Nothing is mounted when no admin keys are configured.
+func (h *AdminHandler) RegisterRoutes(router fiber.Router) {
+	if len(h.config.AdminAPIKeys) == 0 {
+		return
+	}
+	admin := router.Group("/admin", newAPIKeyAuth(adminKeyHeader, h.config.AdminAPIKeys))
+	admin.Get("/config", h.GetConfig)
+	admin.Get("/dashboard", h.GetDashboard)
+	admin.Post("/membership-ids/reissue", h.ReissueMembershipIDs)
+	admin.Get("/users/duplicates", h.GetDuplicateCandidates)
+	admin.Post("/users/:id/reset", h.ResetUser)
+	admin.Post("/tiers/simulate", h.SimulateTiers)
+}
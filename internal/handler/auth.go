@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// Headers carrying the shared secrets: apiKeyHeader under config.AuthModeAPIKey,
+// adminKeyHeader on the admin routes
+const (
+	apiKeyHeader   = "X-API-Key"
+	adminKeyHeader = "X-Admin-Key"
+)
+
+// NewAuth returns middleware that authenticates requests according to
+// cfg.AuthMode. It fails for a mode that cannot be enforced, so a misconfigured
+// deployment does not start open.
+func NewAuth(cfg *config.Config) (fiber.Handler, error) {
+	switch cfg.AuthMode {
+	case "", config.AuthModeNone:
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}, nil
+	case config.AuthModeAPIKey:
+		if len(cfg.APIKeys) == 0 {
+			return nil, fmt.Errorf("%w: %s requires at least one API key", domain.ErrUnsupportedAuthMode, cfg.AuthMode)
+		}
+		return newAPIKeyAuth(apiKeyHeader, cfg.APIKeys), nil
+	}
+	return nil, fmt.Errorf("%w: %q", domain.ErrUnsupportedAuthMode, cfg.AuthMode)
+}
+
+// newAPIKeyAuth responds 401 unless header holds one of keys. Keys are compared
+// as SHA-256 digests in constant time, and every key is checked, so neither the
+// key length nor which key matched leaks through timing.
+func newAPIKeyAuth(header string, keys []string) fiber.Handler {
+	digests := make([][32]byte, len(keys))
+	for i, key := range keys {
+		digests[i] = sha256.Sum256([]byte(key))
+	}
+
+	return func(c *fiber.Ctx) error {
+		provided := sha256.Sum256([]byte(c.Get(header)))
+		match := 0
+		for _, digest := range digests {
+			match |= subtle.ConstantTimeCompare(provided[:], digest[:])
+		}
+		if c.Get(header) == "" || match != 1 {
+			return sendErrorCode(c, 401, codeUnauthorized, "Invalid or missing "+header+" header")
+		}
+		return c.Next()
+	}
+}
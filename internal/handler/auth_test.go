@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+func TestNewAuth_APIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		statusCode int
+	}{
+		{"missing key", "", 401},
+		{"wrong key", "not-a-key", 401},
+		{"first key", "key-one", 200},
+		{"second key", "key-two", 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			auth, err := NewAuth(&config.Config{AuthMode: config.AuthModeAPIKey, APIKeys: []string{"key-one", "key-two"}})
+			assert.NoError(t, err)
+			app := setupTestApp()
+			app.Use(auth)
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+
+			// Act
+			resp, err := app.Test(req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewAuth_None(t *testing.T) {
+	// Arrange
+	auth, err := NewAuth(&config.Config{AuthMode: config.AuthModeNone})
+	assert.NoError(t, err)
+	app := setupTestApp()
+	app.Use(auth)
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestNewAuth_Unsupported(t *testing.T) {
+	for _, cfg := range []*config.Config{
+		{AuthMode: config.AuthModeAPIKey},
+		{AuthMode: config.AuthModeJWT},
+		{AuthMode: "basic"},
+	} {
+		t.Run(cfg.AuthMode, func(t *testing.T) {
+			// Act
+			_, err := NewAuth(cfg)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrUnsupportedAuthMode)
+		})
+	}
+}
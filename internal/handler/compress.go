@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+// NewCompression returns middleware that compresses responses of at least
+// cfg.CompressMinBytes, leaving smaller ones as they are. The handler runs
+// first so the decision is made on the buffered body; streamed bodies of
+// unknown size are always compressed.
+func NewCompression(cfg *config.Config) fiber.Handler {
+	compressor := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {},
+		fasthttp.CompressBrotliDefaultCompression,
+		fasthttp.CompressDefaultCompression,
+	)
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		if !resp.IsBodyStream() && len(resp.Body()) < cfg.CompressMinBytes {
+			return nil
+		}
+		compressor(c.Context())
+		return nil
+	}
+}
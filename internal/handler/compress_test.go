@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+func TestNewCompression_Threshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		bodySize   int
+		compressed bool
+	}{
+		{"small", 100, false},
+		{"large", 4096, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			app := setupTestApp()
+			app.Use(NewCompression(&config.Config{CompressMinBytes: 1024}))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString(strings.Repeat("a", tt.bodySize))
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			// Act
+			resp, err := app.Test(req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 200, resp.StatusCode)
+			if tt.compressed {
+				assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+			} else {
+				assert.Empty(t, resp.Header.Get("Content-Encoding"))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+// dbDurationKey holds the time spent in the use case for the current request
+const dbDurationKey = "db_duration"
+
+// requestTiming is reported under meta.timing in debug mode
+type requestTiming struct {
+	DBMs    float64 `json:"db_ms"`
+	TotalMs float64 `json:"total_ms"`
+}
+
+// NewDebugTiming returns middleware that adds a meta.timing block to JSON object
+// responses when debug mode is on. It does nothing otherwise.
+func NewDebugTiming(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !cfg.DebugMode {
+			return c.Next()
+		}
+
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			return err
+		}
+		return addTimingMeta(c, requestTiming{
+			DBMs:    milliseconds(dbDuration(c)),
+			TotalMs: milliseconds(time.Since(start)),
+		})
+	}
+}
+
+// trackDB starts timing a use case call and returns the function that stops it.
+// Durations add up when a handler makes several calls.
+func trackDB(c *fiber.Ctx) func() {
+	start := time.Now()
+	return func() {
+		c.Locals(dbDurationKey, dbDuration(c)+time.Since(start))
+	}
+}
+
+// dbDuration returns the use case time recorded so far for the request
+func dbDuration(c *fiber.Ctx) time.Duration {
+	duration, _ := c.Locals(dbDurationKey).(time.Duration)
+	return duration
+}
+
+// addTimingMeta merges the timing into the meta object of a JSON object response
+func addTimingMeta(c *fiber.Ctx, timing requestTiming) error {
+	if !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return nil
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+		return nil
+	}
+
+	meta := map[string]interface{}{}
+	if raw, ok := body["meta"]; ok {
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil
+		}
+	}
+	meta["timing"] = timing
+
+	encodedMeta, err := c.App().Config().JSONEncoder(meta)
+	if err != nil {
+		return err
+	}
+	body["meta"] = encodedMeta
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	c.Response().SetBodyRaw(encoded)
+	return nil
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/internal/mocks"
+)
+
+func TestNewDebugTiming(t *testing.T) {
+	tests := []struct {
+		name      string
+		debugMode bool
+	}{
+		{"debug", true},
+		{"production", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := new(mocks.MockUserUseCase)
+			handler := NewUserHandler(mockUseCase)
+			app := setupTestApp()
+			app.Use(NewDebugTiming(&config.Config{DebugMode: tt.debugMode}))
+
+			user := &domain.User{ID: 1, FirstName: "John", Email: "john@example.com"}
+			mockUseCase.On("GetUserByID", uint(1)).Return(user, nil)
+
+			app.Get("/users/:id", handler.GetUser)
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 200, resp.StatusCode)
+
+			var response struct {
+				Data domain.User `json:"data"`
+				Meta *struct {
+					Timing map[string]float64 `json:"timing"`
+				} `json:"meta"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, "John", response.Data.FirstName)
+			if tt.debugMode {
+				assert.NotNil(t, response.Meta)
+				assert.Contains(t, response.Meta.Timing, "db_ms")
+				assert.Contains(t, response.Meta.Timing, "total_ms")
+				assert.GreaterOrEqual(t, response.Meta.Timing["total_ms"], response.Meta.Timing["db_ms"])
+			} else {
+				assert.Nil(t, response.Meta)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewDebugTiming_CamelCase(t *testing.T) {
+	// Arrange
+	app := fiber.New(fiber.Config{JSONEncoder: NewJSONEncoder(config.JSONCaseCamel)})
+	app.Use(NewDebugTiming(&config.Config{DebugMode: true}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"data": "ok"})
+	})
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+
+	// Assert
+	assert.NoError(t, err)
+
+	var response struct {
+		Meta struct {
+			Timing map[string]float64 `json:"timing"`
+		} `json:"meta"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Contains(t, response.Meta.Timing, "dbMs")
+	assert.Contains(t, response.Meta.Timing, "totalMs")
+}
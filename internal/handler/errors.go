@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/pkg/pagination"
+)
+
+// ErrorBody is the error every endpoint responds with, wrapped as {"error": ...}.
+// Code is stable for clients to match on; Message is for people.
+type ErrorBody struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Details []domain.FieldError `json:"details,omitempty"`
+}
+
+// Codes for failures the handlers report without a sentinel error
+const (
+	codeUserNotFound      = "USER_NOT_FOUND"
+	codeInvalidUserID     = "INVALID_USER_ID"
+	codeInvalidParameter  = "INVALID_PARAMETER"
+	codeMissingField      = "MISSING_FIELD"
+	codeInvalidMembership = "INVALID_MEMBERSHIP_ID"
+	codeCardNotFound      = "MEMBERSHIP_CARD_NOT_FOUND"
+	codeUserModified      = "USER_MODIFIED"
+	codeConfirmRequired   = "CONFIRMATION_REQUIRED"
+	codeBatchFailed       = "BATCH_FAILED"
+	codeUnauthorized      = "UNAUTHORIZED"
+	codeRateLimited       = "RATE_LIMITED"
+	codeInternal          = "INTERNAL_ERROR"
+)
+
+// errorCodes maps sentinel errors to their codes, matched in order with errors.Is
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{errEmptyBody, "EMPTY_BODY"},
+	{errInvalidBody, "INVALID_BODY"},
+	{pagination.ErrInvalidCursor, "INVALID_CURSOR"},
+	{domain.ErrValidation, "VALIDATION_FAILED"},
+	{domain.ErrUserNotFound, codeUserNotFound},
+	{domain.ErrCouldNotGenerateID, "MEMBERSHIP_ID_UNAVAILABLE"},
+	{domain.ErrInvalidMembershipType, "INVALID_MEMBERSHIP_TYPE"},
+	{domain.ErrDirectTierEditDenied, "DIRECT_TIER_EDIT_DENIED"},
+	{domain.ErrTierTransitionNotAllowed, "TIER_TRANSITION_NOT_ALLOWED"},
+	{domain.ErrInvalidMembershipID, codeInvalidMembership},
+	{domain.ErrMembershipIDRevoked, "MEMBERSHIP_ID_REVOKED"},
+	{domain.ErrEmailTaken, "EMAIL_TAKEN"},
+	{domain.ErrIdempotencyKeyReused, "IDEMPOTENCY_KEY_REUSED"},
+	{domain.ErrIdempotentUserDeleted, "IDEMPOTENT_USER_DELETED"},
+	{domain.ErrRestoreConflict, "RESTORE_CONFLICT"},
+	{domain.ErrUserModified, codeUserModified},
+	{domain.ErrInvalidStatus, "INVALID_STATUS"},
+	{domain.ErrUserSuspended, "USER_SUSPENDED"},
+	{domain.ErrFieldImmutable, "FIELD_IMMUTABLE"},
+	{domain.ErrNegativePoints, "NEGATIVE_POINTS"},
+	{domain.ErrPointsRateExceeded, "POINTS_RATE_EXCEEDED"},
+	{domain.ErrBelowPointsFloor, "BELOW_POINTS_FLOOR"},
+	{domain.ErrInvalidSort, "INVALID_SORT"},
+	{domain.ErrEmptySearchQuery, "EMPTY_SEARCH_QUERY"},
+	{domain.ErrUnknownComputedField, "UNKNOWN_COMPUTED_FIELD"},
+	{domain.ErrInvalidThresholds, "INVALID_THRESHOLDS"},
+	{domain.ErrInvalidBatch, "INVALID_BATCH"},
+	{domain.ErrInvalidImport, "INVALID_IMPORT"},
+	{domain.ErrInvalidImportMode, "INVALID_IMPORT_MODE"},
+	{domain.ErrImportRejected, "IMPORT_REJECTED"},
+	{domain.ErrImportURLNotAllowed, "IMPORT_URL_NOT_ALLOWED"},
+	{domain.ErrImportFetchFailed, "IMPORT_FETCH_FAILED"},
+	{domain.ErrImportTooLarge, "IMPORT_TOO_LARGE"},
+}
+
+// statusCodes codes errors without a sentinel of their own by response status
+var statusCodes = map[int]string{
+	400: "BAD_REQUEST",
+	401: codeUnauthorized,
+	404: "NOT_FOUND",
+	405: "METHOD_NOT_ALLOWED",
+	409: "CONFLICT",
+	412: "PRECONDITION_FAILED",
+	413: "PAYLOAD_TOO_LARGE",
+	422: "UNPROCESSABLE_ENTITY",
+	429: codeRateLimited,
+	502: "BAD_GATEWAY",
+	503: "SERVICE_UNAVAILABLE",
+}
+
+// errorCode returns the code for err, falling back to one for the response status
+func errorCode(status int, err error) string {
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			return known.code
+		}
+	}
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return codeInternal
+}
+
+// errorBody builds the error envelope for err. Validation errors list their
+// fields as details under the plain "validation failed" message.
+func errorBody(status int, err error) fiber.Map {
+	body := ErrorBody{Code: errorCode(status, err), Message: err.Error()}
+	if validationErr := (*domain.ValidationError)(nil); errors.As(err, &validationErr) {
+		body.Message = domain.ErrValidation.Error()
+		body.Details = validationErr.Fields
+	}
+	return fiber.Map{"error": body}
+}
+
+// sendError responds with status and the error envelope for err
+func sendError(c *fiber.Ctx, status int, err error) error {
+	return c.Status(status).JSON(errorBody(status, err))
+}
+
+// codedErrorBody builds the error envelope with the given code and message
+func codedErrorBody(code, message string) fiber.Map {
+	return fiber.Map{"error": ErrorBody{Code: code, Message: message}}
+}
+
+// sendErrorCode responds with status and an error envelope with the given code and message
+func sendErrorCode(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(codedErrorBody(code, message))
+}
+
+// internalError logs err with the request's correlation ID and responds 500
+// with message, keeping the cause out of the response
+func internalError(c *fiber.Ctx, err error, message string) error {
+	requestLogger(c).Error(message, "error", err)
+	return sendErrorCode(c, 500, codeInternal, message)
+}
+
+// ErrorHandler responds to errors that reach Fiber, such as unknown routes,
+// with the same error envelope as the handlers
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	message := "Internal server error"
+	if fiberErr := (*fiber.Error)(nil); errors.As(err, &fiberErr) {
+		status, message = fiberErr.Code, fiberErr.Message
+	} else {
+		requestLogger(c).Error(message, "error", err)
+	}
+	return sendErrorCode(c, status, errorCode(status, err), message)
+}
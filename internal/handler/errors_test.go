@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// errorResponse decodes the error envelope in handler tests
+type errorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		expected string
+	}{
+		{"sentinel", 400, domain.ErrNegativePoints, "NEGATIVE_POINTS"},
+		{"wrapped sentinel", 400, fmt.Errorf("row 2: %w", domain.ErrInvalidMembershipType), "INVALID_MEMBERSHIP_TYPE"},
+		{"validation error", 400, &domain.ValidationError{}, "VALIDATION_FAILED"},
+		{"unknown error falls back to status", 409, errors.New("boom"), "CONFLICT"},
+		{"unknown status is internal", 500, errors.New("boom"), "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, errorCode(tt.status, tt.err))
+		})
+	}
+}
+
+func TestErrorBody_ValidationDetails(t *testing.T) {
+	// Arrange
+	err := &domain.ValidationError{Fields: []domain.FieldError{
+		{Field: "email", Message: "must be a valid email address"},
+		{Field: "last_name", Message: "is required"},
+	}}
+
+	// Act
+	body := errorBody(400, err)
+
+	// Assert
+	assert.Equal(t, fiber.Map{"error": ErrorBody{
+		Code:    "VALIDATION_FAILED",
+		Message: "validation failed",
+		Details: err.Fields,
+	}}, body)
+}
+
+func TestErrorHandler(t *testing.T) {
+	// Arrange
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/fails", func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+
+	// Act
+	missing, err := app.Test(httptest.NewRequest("GET", "/missing", nil))
+	assert.NoError(t, err)
+	failed, err := app.Test(httptest.NewRequest("GET", "/fails", nil))
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 404, missing.StatusCode)
+	var missingResponse errorResponse
+	assert.NoError(t, json.NewDecoder(missing.Body).Decode(&missingResponse))
+	assert.Equal(t, "NOT_FOUND", missingResponse.Error.Code)
+
+	assert.Equal(t, 500, failed.StatusCode)
+	var failedResponse errorResponse
+	assert.NoError(t, json.NewDecoder(failed.Body).Decode(&failedResponse))
+	assert.Equal(t, ErrorBody{Code: "INTERNAL_ERROR", Message: "Internal server error"}, failedResponse.Error)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/pkg/database"
+)
+
+// Readiness sub-check statuses
+const (
+	checkStatusOK      = "ok"
+	checkStatusFail    = "fail"
+	checkStatusSkipped = "skipped"
+)
+
+// HealthHandler handles HTTP requests that report whether the service can take traffic
+type HealthHandler struct {
+	config *config.Config
+	ping   func() error
+	statFS database.StatFS
+}
+
+// NewHealthHandler creates a new health handler that checks the database with
+// ping and reads disk space with statFS
+func NewHealthHandler(cfg *config.Config, ping func() error, statFS database.StatFS) *HealthHandler {
+	return &HealthHandler{
+		config: cfg,
+		ping:   ping,
+		statFS: statFS,
+	}
+}
+
+// Health handles GET /health, responding 503 when the database is unreachable
+func (h *HealthHandler) Health(c *fiber.Ctx) error {
+	if err := h.ping(); err != nil {
+		return c.Status(503).JSON(fiber.Map{
+			"status":   "degraded",
+			"database": "down",
+		})
+	}
+	return c.JSON(fiber.Map{
+		"status":   "ok",
+		"message":  "KBTG AI Backend Workshop is running!",
+		"database": "up",
+	})
+}
+
+// Live handles GET /health/live, reporting only that the process is up
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+// Ready handles GET /health/ready, responding 503 when any sub-check fails
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	checks := map[string]fiber.Map{
+		"database": h.checkDatabase(),
+		"disk":     h.checkDisk(),
+	}
+
+	for _, check := range checks {
+		if check["status"] == checkStatusFail {
+			return c.Status(503).JSON(fiber.Map{
+				"status": "not_ready",
+				"checks": checks,
+			})
+		}
+	}
+	return c.JSON(fiber.Map{
+		"status": "ready",
+		"checks": checks,
+	})
+}
+
+// checkDatabase reports whether the database answers a ping
+func (h *HealthHandler) checkDatabase() fiber.Map {
+	if err := h.ping(); err != nil {
+		return fiber.Map{"status": checkStatusFail, "error": err.Error()}
+	}
+	return fiber.Map{"status": checkStatusOK}
+}
+
+// checkDisk reports the free space for the database file, so writes stop being
+// accepted before the disk fills
+func (h *HealthHandler) checkDisk() fiber.Map {
+	space, err := database.CheckDiskSpace(h.config.DBPath, h.config.DiskMinFreeBytes, h.statFS)
+	if errors.Is(err, database.ErrDiskStatUnsupported) || (space == nil && err == nil) {
+		return fiber.Map{"status": checkStatusSkipped}
+	}
+
+	result := fiber.Map{"status": checkStatusOK}
+	if err != nil {
+		result["status"] = checkStatusFail
+		result["error"] = err.Error()
+	}
+	if space != nil {
+		result["path"] = space.Path
+		result["available_bytes"] = space.AvailableBytes
+		result["min_free_bytes"] = space.MinFreeBytes
+	}
+	return result
+}
+
+// RegisterRoutes mounts the health endpoints on the given router
+func (h *HealthHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/health", h.Health)
+	router.Get("/health/live", h.Live)
+	router.Get("/health/ready", h.Ready)
+	// Kept for orchestrators already probing the original path
+	router.Get("/ready", h.Ready)
+}
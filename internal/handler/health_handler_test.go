@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/pkg/database"
+)
+
+// pingOK stands in for a reachable database
+func pingOK() error { return nil }
+
+func TestHealthHandler_Health_DatabaseDown(t *testing.T) {
+	// Arrange - a closed connection fails every ping
+	db, err := database.NewDatabase("file:healthdown?mode=memory&cache=shared")
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+	statFS := func(path string) (uint64, error) { return 200, nil }
+	handler := NewHealthHandler(&config.Config{DBPath: "users.db", DiskMinFreeBytes: 100}, db.Ping, statFS)
+	app := setupTestApp()
+	handler.RegisterRoutes(app)
+
+	// Act
+	healthResp, err := app.Test(httptest.NewRequest("GET", "/health", nil))
+	assert.NoError(t, err)
+	liveResp, err := app.Test(httptest.NewRequest("GET", "/health/live", nil))
+	assert.NoError(t, err)
+	readyResp, err := app.Test(httptest.NewRequest("GET", "/health/ready", nil))
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 503, healthResp.StatusCode)
+	var health map[string]string
+	assert.NoError(t, json.NewDecoder(healthResp.Body).Decode(&health))
+	assert.Equal(t, map[string]string{"status": "degraded", "database": "down"}, health)
+
+	assert.Equal(t, 200, liveResp.StatusCode)
+
+	assert.Equal(t, 503, readyResp.StatusCode)
+	var ready struct {
+		Checks map[string]map[string]interface{} `json:"checks"`
+	}
+	assert.NoError(t, json.NewDecoder(readyResp.Body).Decode(&ready))
+	assert.Equal(t, "fail", ready.Checks["database"]["status"])
+	assert.Equal(t, "ok", ready.Checks["disk"]["status"])
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		dbPath     string
+		available  uint64
+		statusCode int
+		status     string
+		diskStatus string
+	}{
+		{"enough space", "users.db", 200, 200, "ready", "ok"},
+		{"low space", "users.db", 50, 503, "not_ready", "fail"},
+		{"in-memory database", ":memory:", 0, 200, "ready", "skipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			statFS := func(path string) (uint64, error) { return tt.available, nil }
+			handler := NewHealthHandler(&config.Config{DBPath: tt.dbPath, DiskMinFreeBytes: 100}, pingOK, statFS)
+			app := setupTestApp()
+			handler.RegisterRoutes(app)
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest("GET", "/ready", nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
+			var response struct {
+				Status string                            `json:"status"`
+				Checks map[string]map[string]interface{} `json:"checks"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, tt.status, response.Status)
+			assert.Equal(t, tt.diskStatus, response.Checks["disk"]["status"])
+		})
+	}
+}
+
+func TestHealthHandler_Ready_UnsupportedPlatform(t *testing.T) {
+	// Arrange
+	statFS := func(path string) (uint64, error) { return 0, database.ErrDiskStatUnsupported }
+	handler := NewHealthHandler(&config.Config{DBPath: "users.db", DiskMinFreeBytes: 100}, pingOK, statFS)
+	app := setupTestApp()
+	handler.RegisterRoutes(app)
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest("GET", "/ready", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+// snakeKey matches snake_case identifiers; data keys such as emails are left alone
+var snakeKey = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)+$`)
+
+// NewJSONEncoder returns the response encoder for the configured key case.
+// Field names stay snake_case in the domain and database; camelCase is only
+// applied when responses are written, and only to struct field names and the
+// keys of fiber.Map envelopes. Keys of other maps are data, such as tier names,
+// and are written as they are.
+func NewJSONEncoder(jsonCase string) utils.JSONMarshal {
+	if jsonCase != config.JSONCaseCamel {
+		return json.Marshal
+	}
+
+	return func(v interface{}) ([]byte, error) {
+		value, err := camelize(reflect.ValueOf(v))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	}
+}
+
+var (
+	fiberMapType      = reflect.TypeOf(fiber.Map{})
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// camelize converts v into a value that marshals like v, with struct field
+// names and fiber.Map keys in camelCase
+func camelize(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, nil
+		}
+		raw, err := json.Marshal(v.Interface())
+		return json.RawMessage(raw), err
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return camelize(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		if err := camelizeFields(v, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface(), nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if v.Type() == fiberMapType {
+				key = toCamel(key)
+			}
+			item, err := camelize(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			out[key] = item
+		}
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		if (v.Kind() == reflect.Slice && v.IsNil()) || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface(), nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			item, err := camelize(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = item
+		}
+		return out, nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// camelizeFields adds the fields encoding/json would write for struct v to out,
+// named from their json tags in camelCase. Embedded structs without a tag are flattened.
+func camelizeFields(v reflect.Value, out map[string]interface{}) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		value := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := camelizeFields(embedded, out); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "omitempty") && isEmptyValue(value) {
+			continue
+		}
+
+		item, err := camelize(value)
+		if err != nil {
+			return err
+		}
+		out[toCamel(name)] = item
+	}
+	return nil
+}
+
+// isEmptyValue reports whether a field tagged omitempty is left out, as encoding/json decides it
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	default:
+		return v.IsZero()
+	}
+}
+
+// toCamel converts a snake_case key such as first_name to firstName
+func toCamel(key string) string {
+	if !snakeKey.MatchString(key) {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
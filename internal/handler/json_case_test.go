@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/internal/mocks"
+)
+
+func TestNewJSONEncoder_ResponseKeys(t *testing.T) {
+	tests := []struct {
+		jsonCase string
+		present  string
+		absent   string
+	}{
+		{config.JSONCaseSnake, "first_name", "firstName"},
+		{config.JSONCaseCamel, "firstName", "first_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jsonCase, func(t *testing.T) {
+			// Arrange
+			mockUseCase := new(mocks.MockUserUseCase)
+			handler := NewUserHandler(mockUseCase)
+			app := fiber.New(fiber.Config{JSONEncoder: NewJSONEncoder(tt.jsonCase)})
+
+			user := &domain.User{ID: 1, FirstName: "John", Email: "john_doe@example.com"}
+			mockUseCase.On("GetUserByID", uint(1)).Return(user, nil)
+
+			app.Get("/users/:id", handler.GetUser)
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 200, resp.StatusCode)
+
+			var response map[string]map[string]interface{}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, "John", response["data"][tt.present])
+			assert.NotContains(t, response["data"], tt.absent)
+			assert.Equal(t, "john_doe@example.com", response["data"]["email"])
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestToCamel(t *testing.T) {
+	assert.Equal(t, "membershipId", toCamel("membership_id"))
+	assert.Equal(t, "id", toCamel("id"))
+	assert.Equal(t, "john_doe@example.com", toCamel("john_doe@example.com"))
+}
+
+func TestNewJSONEncoder_KeepsMapKeys(t *testing.T) {
+	// Arrange
+	encode := NewJSONEncoder(config.JSONCaseCamel)
+	cfg := &config.Config{PointsFloors: map[string]int{"gold_plus": 1000}, PointsFloorPolicy: config.PointsFloorClamp}
+
+	// Act
+	raw, err := encode(fiber.Map{"data": cfg, "next_cursor": "abc"})
+
+	// Assert
+	assert.NoError(t, err)
+	var response struct {
+		Data       map[string]interface{} `json:"data"`
+		NextCursor string                 `json:"nextCursor"`
+	}
+	assert.NoError(t, json.Unmarshal(raw, &response))
+	assert.Equal(t, map[string]interface{}{"gold_plus": float64(1000)}, response.Data["pointsFloors"])
+	assert.Equal(t, "clamp", response.Data["pointsFloorPolicy"])
+	assert.NotContains(t, response.Data, "points_floors")
+	assert.Equal(t, "abc", response.NextCursor)
+}
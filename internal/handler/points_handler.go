@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// PointsHandler handles HTTP requests for the points ledger
+type PointsHandler struct {
+	pointsUseCase domain.PointsUseCase
+}
+
+// NewPointsHandler creates a new points handler
+func NewPointsHandler(pointsUseCase domain.PointsUseCase) *PointsHandler {
+	return &PointsHandler{
+		pointsUseCase: pointsUseCase,
+	}
+}
+
+// GetHistory handles GET /users/:id/points/history
+func (h *PointsHandler) GetHistory(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil || id == 0 {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	stop := trackDB(c)
+	txns, err := h.pointsUseCase.GetHistory(uint(id))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		return internalError(c, err, "Failed to retrieve points history")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  txns,
+		"count": len(txns),
+	})
+}
+
+// RegisterRoutes mounts the points ledger endpoints on the given router
+func (h *PointsHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/users/:id/points/history", h.GetHistory)
+}
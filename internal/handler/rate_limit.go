@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+// NewRateLimiter returns middleware that allows each client IP at most
+// cfg.RateLimitRequests requests per cfg.RateLimitWindow, answering 429 with a
+// Retry-After header beyond that. A zero limit lets every request through.
+func NewRateLimiter(cfg *config.Config) fiber.Handler {
+	if cfg.RateLimitRequests <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        cfg.RateLimitRequests,
+		Expiration: cfg.RateLimitWindow,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return sendErrorCode(c, 429, codeRateLimited, "Too many requests")
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		lastStatus int
+	}{
+		{"limit exceeded", 3, 429},
+		{"disabled", 0, 201},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			app := setupTestApp()
+			app.Use(NewRateLimiter(&config.Config{RateLimitRequests: tt.limit, RateLimitWindow: time.Minute}))
+			app.Post("/users", func(c *fiber.Ctx) error {
+				return c.SendStatus(201)
+			})
+
+			// Act - one request more than the limit
+			var statuses []int
+			var retryAfter string
+			for i := 0; i <= 3; i++ {
+				resp, err := app.Test(httptest.NewRequest("POST", "/users", nil))
+				assert.NoError(t, err)
+				statuses = append(statuses, resp.StatusCode)
+				retryAfter = resp.Header.Get(fiber.HeaderRetryAfter)
+			}
+
+			// Assert
+			assert.Equal(t, []int{201, 201, 201, tt.lastStatus}, statuses)
+			assert.Equal(t, tt.lastStatus == 429, retryAfter != "")
+		})
+	}
+}
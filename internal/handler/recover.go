@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+// NewRecover returns middleware that turns a panic into a 500 JSON response and
+// logs the stack. The stack is added to the response only when EXPOSE_STACK and
+// debug mode are both on, and never in production.
+func NewRecover(cfg *config.Config) fiber.Handler {
+	exposeStack := cfg.ExposeStack && cfg.DebugMode && cfg.AppEnv != config.EnvProd
+
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			stack := string(debug.Stack())
+			requestLogger(c).Error("panic", "panic", fmt.Sprint(r), "stack", stack)
+
+			response := codedErrorBody(codeInternal, "Internal server error")
+			if exposeStack {
+				response["panic"] = fmt.Sprint(r)
+				response["stack"] = stack
+			}
+			err = c.Status(500).JSON(response)
+		}()
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+)
+
+func TestNewRecover(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		exposeStack bool
+	}{
+		{"debug with expose stack", config.Config{DebugMode: true, ExposeStack: true}, true},
+		{"expose stack without debug", config.Config{ExposeStack: true}, false},
+		{"debug without expose stack", config.Config{DebugMode: true}, false},
+		{"production", config.Config{DebugMode: true, ExposeStack: true, AppEnv: config.EnvProd}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			app := setupTestApp()
+			app.Use(NewRecover(&tt.cfg))
+			app.Get("/panic", func(c *fiber.Ctx) error {
+				panic("boom")
+			})
+
+			// Act
+			resp, err := app.Test(httptest.NewRequest("GET", "/panic", nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 500, resp.StatusCode)
+
+			var response map[string]json.RawMessage
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			var errBody ErrorBody
+			assert.NoError(t, json.Unmarshal(response["error"], &errBody))
+			assert.Equal(t, ErrorBody{Code: "INTERNAL_ERROR", Message: "Internal server error"}, errBody)
+			if tt.exposeStack {
+				assert.JSONEq(t, `"boom"`, string(response["panic"]))
+				assert.Contains(t, string(response["stack"]), "runtime/debug.Stack")
+			} else {
+				assert.NotContains(t, response, "panic")
+				assert.NotContains(t, response, "stack")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"kbtg.tech/ai-backend-workshop/pkg/logging"
+)
+
+// maxRequestIDLength caps client-supplied request IDs; longer ones are replaced
+const maxRequestIDLength = 128
+
+// NewRequestLogger returns middleware that tags each request with a correlation
+// ID, echoed in the X-Request-ID response header, and logs one record per
+// request once it has been handled. A client-supplied X-Request-ID is kept so
+// the ID can be followed across services.
+func NewRequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = utils.UUIDv4()
+		}
+		c.Set(fiber.HeaderXRequestID, id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+
+		// Errors are rendered here rather than after the middleware chain so the
+		// logged status matches the response
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(c.UserContext()).Log(c.UserContext(), level, "request",
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"latency_ms", milliseconds(time.Since(start)),
+			"ip", c.IP(),
+		)
+		return nil
+	}
+}
+
+// requestLogger returns the logger for the request, tagged with its correlation ID
+func requestLogger(c *fiber.Ctx) *slog.Logger {
+	return logging.FromContext(c.UserContext())
+}
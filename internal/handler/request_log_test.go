@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureLogs sends default log records to a buffer as JSON until the returned function is called
+func captureLogs() (*bytes.Buffer, func()) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	return &buf, func() { slog.SetDefault(previous) }
+}
+
+// logRecords decodes every JSON log record in buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNewRequestLogger(t *testing.T) {
+	// Arrange
+	buf, restore := captureLogs()
+	defer restore()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRequestLogger())
+	app.Get("/ok", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	// Act
+	generated, err := app.Test(httptest.NewRequest("GET", "/ok", nil))
+	assert.NoError(t, err)
+	req := httptest.NewRequest("GET", "/ok", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	supplied, err := app.Test(req)
+	assert.NoError(t, err)
+
+	// Assert
+	generatedID := generated.Header.Get("X-Request-ID")
+	assert.Len(t, generatedID, 36)
+	assert.Equal(t, "req-123", supplied.Header.Get("X-Request-ID"))
+
+	records := logRecords(t, buf)
+	assert.Len(t, records, 2)
+	assert.Equal(t, generatedID, records[0]["request_id"])
+	assert.Equal(t, "req-123", records[1]["request_id"])
+	assert.Equal(t, "GET", records[1]["method"])
+	assert.Equal(t, "/ok", records[1]["path"])
+	assert.Equal(t, float64(200), records[1]["status"])
+}
+
+func TestNewRequestLogger_InternalError(t *testing.T) {
+	// Arrange
+	buf, restore := captureLogs()
+	defer restore()
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRequestLogger())
+	app.Get("/fails", func(c *fiber.Ctx) error {
+		return internalError(c, errors.New("database is locked"), "Failed to retrieve users")
+	})
+	req := httptest.NewRequest("GET", "/fails", nil)
+	req.Header.Set("X-Request-ID", "req-500")
+
+	// Act
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	var response errorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, "Failed to retrieve users", response.Error.Message) // the cause stays in the logs
+
+	records := logRecords(t, buf)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "database is locked", records[0]["error"])
+	assert.Equal(t, "req-500", records[0]["request_id"])
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, float64(500), records[1]["status"])
+}
@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/pkg/pagination"
 )
 
 // UserHandler handles HTTP requests for user operations
@@ -21,11 +28,32 @@ func NewUserHandler(userUseCase domain.UserUseCase) *UserHandler {
 
 // GetUsers handles GET /users
 func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
+	opts := domain.UserListOptions{
+		SortBy:          c.Query("sort"),
+		Order:           c.Query("order"),
+		MembershipTypes: splitQueryList(c.Query("membership_type")),
+		Status:          c.Query("status"),
+		IncludeDeleted:  c.QueryBool("include_deleted"),
+	}
+	if cursor, ok, err := pagination.CursorFromQuery(c); ok {
+		if err != nil {
+			return sendError(c, 400, err)
+		}
+		return h.getUsersAfter(c, cursor, opts)
+	}
+	if params, paginated := pagination.FromQuery(c); paginated {
+		opts.Page, opts.Limit = params.Page, params.Limit
+		return h.getUsersPage(c, params, opts)
+	}
+	if opts.SortBy != "" || opts.Order != "" || len(opts.MembershipTypes) > 0 || opts.Status != "" || opts.IncludeDeleted {
+		return h.getUsersPage(c, pagination.Params{}, opts)
+	}
+
+	stop := trackDB(c)
 	users, err := h.userUseCase.GetAllUsers()
+	stop()
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to retrieve users",
-		})
+		return internalError(c, err, "Failed to retrieve users")
 	}
 
 	return c.JSON(fiber.Map{
@@ -34,53 +62,224 @@ func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
 	})
 }
 
-// GetUser handles GET /users/:id
+// getUsersPage responds with users listed by opts. When params holds a page,
+// the response also carries pagination details and navigation links.
+func (h *UserHandler) getUsersPage(c *fiber.Ctx, params pagination.Params, opts domain.UserListOptions) error {
+	stop := trackDB(c)
+	users, total, err := h.userUseCase.ListUsers(opts)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSort) || errors.Is(err, domain.ErrInvalidMembershipType) ||
+			errors.Is(err, domain.ErrInvalidStatus) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to retrieve users")
+	}
+
+	if params.Limit == 0 {
+		return c.JSON(fiber.Map{
+			"data":  users,
+			"count": len(users),
+		})
+	}
+
+	meta := pagination.NewMeta(params, total)
+	pagination.SetLinkHeader(c, meta)
+
+	return c.JSON(fiber.Map{
+		"data":       users,
+		"count":      len(users),
+		"pagination": meta,
+	})
+}
+
+// getUsersAfter responds with the users after the cursor in id order, and the
+// cursor for the next page, which is null once the last user has been listed
+func (h *UserHandler) getUsersAfter(c *fiber.Ctx, cursor pagination.Cursor, opts domain.UserListOptions) error {
+	if opts.SortBy != "" || opts.Order != "" {
+		return sendError(c, 400, fmt.Errorf("%w: cursor pagination always orders by id", domain.ErrInvalidSort))
+	}
+	// Fetch one extra user to tell whether another page follows
+	opts.AfterID, opts.Page, opts.Limit = cursor.After, 1, cursor.Limit+1
+
+	stop := trackDB(c)
+	users, _, err := h.userUseCase.ListUsers(opts)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMembershipType) || errors.Is(err, domain.ErrInvalidStatus) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to retrieve users")
+	}
+
+	var next *uint
+	if len(users) > cursor.Limit {
+		users = users[:cursor.Limit]
+		next = &users[len(users)-1].ID
+	}
+	return c.JSON(fiber.Map{
+		"data":        users,
+		"count":       len(users),
+		"next_cursor": next,
+	})
+}
+
+// SearchUsers handles GET /users/search?q=, always returning a bounded page of matches
+func (h *UserHandler) SearchUsers(c *fiber.Ctx) error {
+	params, _ := pagination.FromQuery(c)
+	params = pagination.NewParams(params.Page, params.Limit)
+
+	stop := trackDB(c)
+	users, total, err := h.userUseCase.SearchUsers(c.Query("q"), domain.UserListOptions{Page: params.Page, Limit: params.Limit})
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrEmptySearchQuery) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to search users")
+	}
+
+	meta := pagination.NewMeta(params, total)
+	pagination.SetLinkHeader(c, meta)
+
+	return c.JSON(fiber.Map{
+		"data":       users,
+		"count":      len(users),
+		"pagination": meta,
+	})
+}
+
+// GetUserChanges handles GET /users/changes?since=<RFC 3339 time>. Omitting
+// since returns every user.
+func (h *UserHandler) GetUserChanges(c *fiber.Ctx) error {
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return sendErrorCode(c, 400, codeInvalidParameter, "since must be an RFC 3339 timestamp")
+		}
+		since = parsed
+	}
+
+	stop := trackDB(c)
+	changes, next, err := h.userUseCase.GetUserChanges(since)
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to retrieve user changes")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":       changes,
+		"count":      len(changes),
+		"next_since": next.Format(time.RFC3339Nano),
+	})
+}
+
+// GetUser handles GET /users/:id, adding any computed fields requested with
+// ?compute=membership_days,rank
 func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
 	}
 
+	stop := trackDB(c)
 	user, err := h.userUseCase.GetUserByID(uint(id))
+	stop()
 	if err != nil {
-		if err.Error() == "user not found" {
-			return c.Status(404).JSON(fiber.Map{
-				"error": "User not found",
-			})
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to retrieve user",
-		})
+		return internalError(c, err, "Failed to retrieve user")
 	}
 
-	return c.JSON(fiber.Map{
+	response := fiber.Map{
 		"data": user,
+	}
+	if fields := splitQueryList(c.Query("compute")); len(fields) > 0 {
+		stop := trackDB(c)
+		computed, err := h.userUseCase.ComputeFields(user, fields)
+		stop()
+		if err != nil {
+			if errors.Is(err, domain.ErrUnknownComputedField) {
+				return sendError(c, 400, err)
+			}
+			return internalError(c, err, "Failed to compute user fields")
+		}
+		response["computed"] = computed
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag())
+	return c.JSON(response)
+}
+
+// GetTierHistory handles GET /users/:id/tier-history
+func (h *UserHandler) GetTierHistory(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	stop := trackDB(c)
+	changes, err := h.userUseCase.GetTierHistory(uint(id))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		return internalError(c, err, "Failed to retrieve tier history")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  changes,
+		"count": len(changes),
 	})
 }
 
-// CreateUser handles POST /users
+// idempotencyKeyHeader lets a client retry POST /users without creating the user twice
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// CreateUser handles POST /users. A repeated Idempotency-Key replays the
+// original 201 with Idempotent-Replayed set instead of creating another user.
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	var req domain.CreateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
 	}
 
-	user, err := h.userUseCase.CreateUser(req)
+	stop := trackDB(c)
+	var user *domain.User
+	var err error
+	if key := c.Get(idempotencyKeyHeader); key != "" {
+		var replayed bool
+		user, replayed, err = h.userUseCase.CreateUserIdempotent(key, req)
+		if replayed {
+			c.Set("Idempotent-Replayed", "true")
+		}
+	} else {
+		user, err = h.userUseCase.CreateUser(req)
+	}
+	stop()
 	if err != nil {
-		if err.Error() == "first name, last name, and email are required" ||
-			err.Error() == "user with this email already exists" {
-			return c.Status(400).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+		if validationErr := (*domain.ValidationError)(nil); errors.As(err, &validationErr) {
+			return sendError(c, 400, validationErr)
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to create user",
-		})
+		if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+			return sendError(c, 422, err)
+		}
+		if errors.Is(err, domain.ErrIdempotentUserDeleted) {
+			return sendError(c, 410, err)
+		}
+		if errors.Is(err, domain.ErrEmailTaken) ||
+			errors.Is(err, domain.ErrInvalidMembershipType) {
+			return sendError(c, 400, err)
+		}
+		if errors.Is(err, domain.ErrCouldNotGenerateID) {
+			return sendErrorCode(c, 500, errorCode(500, err), "Failed to create user: "+err.Error())
+		}
+		return internalError(c, err, "Failed to create user")
 	}
 
 	return c.Status(201).JSON(fiber.Map{
@@ -93,33 +292,185 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
 	}
 
 	var req domain.UpdateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
 	}
 
+	stop := trackDB(c)
 	user, err := h.userUseCase.UpdateUser(uint(id), req)
+	stop()
 	if err != nil {
-		if err.Error() == "user not found" {
-			return c.Status(404).JSON(fiber.Map{
-				"error": "User not found",
-			})
+		if validationErr := (*domain.ValidationError)(nil); errors.As(err, &validationErr) {
+			return sendError(c, 400, validationErr)
 		}
-		if err.Error() == "user with this email already exists" {
-			return c.Status(400).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to update user",
-		})
+		if errors.Is(err, domain.ErrEmailTaken) ||
+			errors.Is(err, domain.ErrInvalidMembershipType) ||
+			errors.Is(err, domain.ErrTierTransitionNotAllowed) ||
+			errors.Is(err, domain.ErrFieldImmutable) ||
+			errors.Is(err, domain.ErrBelowPointsFloor) {
+			return sendError(c, 400, err)
+		}
+		if errors.Is(err, domain.ErrDirectTierEditDenied) {
+			return sendError(c, 403, err)
+		}
+		if errors.Is(err, domain.ErrPointsRateExceeded) {
+			return sendError(c, 429, err)
+		}
+		if errors.Is(err, domain.ErrUserSuspended) {
+			return sendError(c, 409, err)
+		}
+		return internalError(c, err, "Failed to update user")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+// SetPoints handles PUT /users/:id/points
+func (h *UserHandler) SetPoints(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	var req domain.SetPointsRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+	if req.Points == nil {
+		return sendErrorCode(c, 400, codeMissingField, "points is required")
+	}
+
+	stop := trackDB(c)
+	user, txn, err := h.userUseCase.SetPoints(uint(id), *req.Points)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		if errors.Is(err, domain.ErrNegativePoints) {
+			return sendError(c, 400, err)
+		}
+		if errors.Is(err, domain.ErrPointsRateExceeded) {
+			return sendError(c, 429, err)
+		}
+		if errors.Is(err, domain.ErrUserSuspended) {
+			return sendError(c, 409, err)
+		}
+		return internalError(c, err, "Failed to set points")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        user,
+		"transaction": txn,
+	})
+}
+
+// AddPoints handles POST /users/:id/points
+func (h *UserHandler) AddPoints(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	var req domain.AddPointsRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+	if req.Delta == nil {
+		return sendErrorCode(c, 400, codeMissingField, "delta is required")
+	}
+
+	stop := trackDB(c)
+	user, err := h.userUseCase.AddPoints(uint(id), *req.Delta)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		if errors.Is(err, domain.ErrNegativePoints) || errors.Is(err, domain.ErrBelowPointsFloor) {
+			return sendError(c, 400, err)
+		}
+		if errors.Is(err, domain.ErrPointsRateExceeded) {
+			return sendError(c, 429, err)
+		}
+		if errors.Is(err, domain.ErrUserSuspended) || errors.Is(err, domain.ErrUserModified) {
+			return sendError(c, 409, err)
+		}
+		return internalError(c, err, "Failed to add points")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+// ReissueCard handles POST /users/:id/card/reissue
+func (h *UserHandler) ReissueCard(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	stop := trackDB(c)
+	change, err := h.userUseCase.ReissueMembershipID(uint(id))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		return internalError(c, err, "Failed to reissue membership card")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": change,
+	})
+}
+
+// GetUserByMembershipID handles GET /users/by-membership/:code
+func (h *UserHandler) GetUserByMembershipID(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	user, err := h.userUseCase.GetUserByMembershipID(c.Params("code"))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		if errors.Is(err, domain.ErrInvalidMembershipID) {
+			return sendErrorCode(c, 400, codeInvalidMembership, "Invalid membership ID")
+		}
+		return internalError(c, err, "Failed to retrieve user")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+// VerifyCard handles GET /users/verify-card/:membership_id
+func (h *UserHandler) VerifyCard(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	user, err := h.userUseCase.VerifyMembershipID(c.Params("membership_id"))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrMembershipIDRevoked) {
+			return sendError(c, 403, err)
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeCardNotFound, "Membership card not found")
+		}
+		return internalError(c, err, "Failed to verify membership card")
 	}
 
 	return c.JSON(fiber.Map{
@@ -132,24 +483,477 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
 	}
 
-	err = h.userUseCase.DeleteUser(uint(id))
+	// Honor If-Match so a stale client cannot delete a record it has not seen.
+	// The revision is checked by the delete itself rather than read beforehand.
+	stop := trackDB(c)
+	if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && !matchesAny(ifMatch) {
+		err = h.userUseCase.DeleteUserIfUnmodified(uint(id), ifMatchRevisions(ifMatch, uint(id)))
+	} else {
+		err = h.userUseCase.DeleteUser(uint(id))
+	}
+	stop()
 	if err != nil {
-		if err.Error() == "user not found" {
-			return c.Status(404).JSON(fiber.Map{
-				"error": "User not found",
-			})
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to delete user",
-		})
+		if errors.Is(err, domain.ErrUserModified) {
+			return sendErrorCode(c, 412, codeUserModified, "User has been modified")
+		}
+		return internalError(c, err, "Failed to delete user")
 	}
 
 	return c.JSON(fiber.Map{
 		"message": "User deleted successfully",
 	})
 }
+
+// RestoreUser handles POST /users/:id/restore
+func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	stop := trackDB(c)
+	user, err := h.userUseCase.RestoreUser(uint(id))
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		if errors.Is(err, domain.ErrRestoreConflict) {
+			return sendError(c, 409, err)
+		}
+		return internalError(c, err, "Failed to restore user")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+// SuspendUser handles POST /users/:id/suspend
+func (h *UserHandler) SuspendUser(c *fiber.Ctx) error {
+	return h.updateStatus(c, domain.UserStatusSuspended)
+}
+
+// ReactivateUser handles POST /users/:id/reactivate
+func (h *UserHandler) ReactivateUser(c *fiber.Ctx) error {
+	return h.updateStatus(c, domain.UserStatusActive)
+}
+
+// updateStatus moves the user in the path to status and responds with the user
+func (h *UserHandler) updateStatus(c *fiber.Ctx, status string) error {
+	idParam := c.Params("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return sendErrorCode(c, 400, codeInvalidUserID, "Invalid user ID")
+	}
+
+	stop := trackDB(c)
+	user, err := h.userUseCase.UpdateStatus(uint(id), status)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return sendErrorCode(c, 404, codeUserNotFound, "User not found")
+		}
+		return internalError(c, err, "Failed to update user status")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": user,
+	})
+}
+
+// DeleteUsers handles POST /users/batch-delete
+func (h *UserHandler) DeleteUsers(c *fiber.Ctx) error {
+	var req domain.BatchDeleteRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+
+	stop := trackDB(c)
+	results, err := h.userUseCase.DeleteUsers(req.IDs)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to delete users")
+	}
+
+	deleted := 0
+	for _, result := range results {
+		if result.Status == domain.BatchDeleteDeleted {
+			deleted++
+		}
+	}
+
+	status := batchStatus(deleted, len(results))
+	return c.Status(status).JSON(batchBody(status, "No users were deleted", fiber.Map{
+		"data": results,
+	}))
+}
+
+// GetTotalPoints handles GET /users/points/total
+func (h *UserHandler) GetTotalPoints(c *fiber.Ctx) error {
+	membershipType := c.Query("membership_type")
+
+	stop := trackDB(c)
+	total, err := h.userUseCase.GetTotalPoints(membershipType)
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to calculate total points")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fiber.Map{
+			"total_points":    total,
+			"membership_type": membershipType,
+		},
+	})
+}
+
+// GetStats handles GET /users/stats
+func (h *UserHandler) GetStats(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	stats, err := h.userUseCase.GetUserStats(c.Query("membership_type"))
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to calculate stats")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": stats,
+	})
+}
+
+// GetStatsCSV handles GET /users/stats.csv
+func (h *UserHandler) GetStatsCSV(c *fiber.Ctx) error {
+	stop := trackDB(c)
+	stats, err := h.userUseCase.GetTierStats(c.Query("membership_type"))
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to calculate stats")
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write(domain.TierStatsCSVColumns)
+	for _, s := range stats {
+		_ = writer.Write([]string{
+			s.MembershipType,
+			strconv.FormatInt(s.Count, 10),
+			strconv.FormatInt(s.TotalPoints, 10),
+			strconv.FormatFloat(s.AveragePoints, 'f', 2, 64),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return internalError(c, err, "Failed to write stats")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="stats.csv"`)
+	return c.Send(buf.Bytes())
+}
+
+// GetLeaderboard handles GET /users/leaderboard
+func (h *UserHandler) GetLeaderboard(c *fiber.Ctx) error {
+	opts := domain.LeaderboardOptions{
+		MembershipType: c.Query("membership_type"),
+		Limit:          c.QueryInt("limit"),
+	}
+	if value := c.Query("min_points"); value != "" {
+		minPoints, err := strconv.Atoi(value)
+		if err != nil {
+			return sendErrorCode(c, 400, codeInvalidParameter, "Invalid min_points")
+		}
+		opts.MinPoints = &minPoints
+	}
+
+	stop := trackDB(c)
+	entries, err := h.userUseCase.GetLeaderboard(opts)
+	stop()
+	if err != nil {
+		return internalError(c, err, "Failed to retrieve leaderboard")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  entries,
+		"count": len(entries),
+	})
+}
+
+// ImportUsersFromURL handles POST /users/import/url
+func (h *UserHandler) ImportUsersFromURL(c *fiber.Ctx) error {
+	var req domain.ImportURLRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+	if req.URL == "" {
+		return sendError(c, 400, errInvalidBody)
+	}
+
+	stop := trackDB(c)
+	results, err := h.userUseCase.ImportUsersFromURL(c.UserContext(), req.URL)
+	stop()
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrImportURLNotAllowed), errors.Is(err, domain.ErrInvalidImport):
+			return sendError(c, 400, err)
+		case errors.Is(err, domain.ErrImportTooLarge):
+			body := errorBody(413, err)
+			body["data"] = results
+			return c.Status(413).JSON(body)
+		case errors.Is(err, domain.ErrImportFetchFailed):
+			return sendError(c, 502, err)
+		}
+		requestLogger(c).Error("Failed to import users", "error", err)
+		body := codedErrorBody(codeInternal, "Failed to import users")
+		body["data"] = results
+		return c.Status(500).JSON(body)
+	}
+
+	created := 0
+	for _, result := range results {
+		if result.Status == domain.ImportRowCreated {
+			created++
+		}
+	}
+
+	status := batchStatus(created, len(results))
+	return c.Status(status).JSON(batchBody(status, "No users were imported", fiber.Map{
+		"data":    results,
+		"created": created,
+		"failed":  len(results) - created,
+	}))
+}
+
+// ImportPoints handles POST /users/points/import?mode=atomic|partial with a CSV body
+func (h *UserHandler) ImportPoints(c *fiber.Ctx) error {
+	if len(c.Body()) == 0 {
+		return sendError(c, 400, errEmptyBody)
+	}
+
+	stop := trackDB(c)
+	results, err := h.userUseCase.ImportPoints(bytes.NewReader(c.Body()), c.Query("mode"))
+	stop()
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidImportMode), errors.Is(err, domain.ErrInvalidImport):
+			return sendError(c, 400, err)
+		case errors.Is(err, domain.ErrImportRejected):
+			body := errorBody(422, err)
+			body["data"] = results
+			return c.Status(422).JSON(body)
+		}
+		requestLogger(c).Error("Failed to import points", "error", err)
+		body := codedErrorBody(codeInternal, "Failed to import points")
+		body["data"] = results
+		return c.Status(500).JSON(body)
+	}
+
+	applied := 0
+	for _, result := range results {
+		if result.Status == domain.ImportRowApplied {
+			applied++
+		}
+	}
+
+	status := batchStatus(applied, len(results))
+	return c.Status(status).JSON(batchBody(status, "No points adjustments were applied", fiber.Map{
+		"data":    results,
+		"applied": applied,
+		"failed":  len(results) - applied,
+	}))
+}
+
+// GetImportTemplate handles GET /users/import/template.csv
+func (h *UserHandler) GetImportTemplate(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(domain.UserCSVColumns); err != nil {
+		return internalError(c, err, "Failed to build import template")
+	}
+	writer.Flush()
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="template.csv"`)
+	return c.Send(buf.Bytes())
+}
+
+// CheckEmailsExist handles POST /users/exists
+func (h *UserHandler) CheckEmailsExist(c *fiber.Ctx) error {
+	var req domain.EmailsExistRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+
+	stop := trackDB(c)
+	exists, err := h.userUseCase.CheckEmailsExist(req.Emails)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to check emails")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": exists,
+	})
+}
+
+// NormalizeContacts handles POST /utils/normalize
+func (h *UserHandler) NormalizeContacts(c *fiber.Ctx) error {
+	var req domain.NormalizeRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+
+	result, err := h.userUseCase.NormalizeContacts(req)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to normalize values")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": result,
+	})
+}
+
+// GetUsersByMembershipIDs handles POST /users/batch/by-membership
+func (h *UserHandler) GetUsersByMembershipIDs(c *fiber.Ctx) error {
+	var req domain.MembershipIDsRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendError(c, 400, err)
+	}
+
+	stop := trackDB(c)
+	users, notFound, err := h.userUseCase.GetUsersByMembershipIDs(req.MembershipIDs)
+	stop()
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			return sendError(c, 400, err)
+		}
+		return internalError(c, err, "Failed to retrieve users")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":      users,
+		"count":     len(users),
+		"not_found": notFound,
+	})
+}
+
+// splitQueryList splits a comma-separated query value, dropping empty entries
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Errors reported for request bodies that cannot be parsed
+var (
+	errEmptyBody   = errors.New("request body is required")
+	errInvalidBody = errors.New("Invalid request body")
+)
+
+// parseBody decodes the request body into out, telling an empty body apart from a malformed one
+func parseBody(c *fiber.Ctx, out interface{}) error {
+	if len(bytes.TrimSpace(c.Body())) == 0 {
+		return errEmptyBody
+	}
+	if err := c.BodyParser(out); err != nil {
+		return errInvalidBody
+	}
+	return nil
+}
+
+// batchStatus picks the status for a bulk operation: 200 when every entry
+// succeeded (or there were none), 400 when every entry failed and 207
+// Multi-Status for a mix, so clients can detect partial failure
+func batchStatus(succeeded, total int) int {
+	switch {
+	case succeeded == total:
+		return 200
+	case succeeded == 0:
+		return 400
+	}
+	return 207
+}
+
+// batchBody adds the error envelope, with message, to the body of a bulk
+// response when batchStatus found every entry failed, keeping the per-entry results
+func batchBody(status int, message string, body fiber.Map) fiber.Map {
+	if status == 400 {
+		body["error"] = ErrorBody{Code: codeBatchFailed, Message: message}
+	}
+	return body
+}
+
+// matchesAny reports whether an If-Match header value is the "*" wildcard
+func matchesAny(ifMatch string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(candidate) == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchRevisions returns the revisions of user id named by the ETags in an
+// If-Match header value, skipping ETags of other users or in another format
+func ifMatchRevisions(ifMatch string, id uint) []time.Time {
+	var revisions []time.Time
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if etagID, updatedAt, ok := domain.ParseETag(strings.TrimSpace(candidate)); ok && etagID == id {
+			revisions = append(revisions, updatedAt)
+		}
+	}
+	return revisions
+}
+
+// RegisterRoutes mounts the user endpoints on the given router
+func (h *UserHandler) RegisterRoutes(router fiber.Router) {
+	users := router.Group("/users")
+	users.Get("/", h.GetUsers)
+	users.Get("/points/total", h.GetTotalPoints)
+	users.Get("/leaderboard", h.GetLeaderboard)
+	users.Get("/search", h.SearchUsers)
+	users.Get("/changes", h.GetUserChanges)
+	users.Get("/stats", h.GetStats)
+	users.Get("/stats.csv", h.GetStatsCSV)
+	users.Get("/import/template.csv", h.GetImportTemplate)
+	users.Get("/verify-card/:membership_id", h.VerifyCard)
+	users.Get("/by-membership/:code", h.GetUserByMembershipID)
+	users.Get("/:id", h.GetUser)
+	users.Get("/:id/tier-history", h.GetTierHistory)
+	users.Post("/", h.CreateUser)
+	users.Post("/batch-delete", h.DeleteUsers)
+	users.Post("/batch/by-membership", h.GetUsersByMembershipIDs)
+	users.Post("/exists", h.CheckEmailsExist)
+	users.Post("/:id/points", h.AddPoints)
+	users.Post("/:id/restore", h.RestoreUser)
+	users.Post("/:id/suspend", h.SuspendUser)
+	users.Post("/:id/reactivate", h.ReactivateUser)
+	users.Post("/:id/card/reissue", h.ReissueCard)
+	users.Post("/import/url", h.ImportUsersFromURL)
+	users.Post("/points/import", h.ImportPoints)
+	users.Put("/:id", h.UpdateUser)
+	users.Put("/:id/points", h.SetPoints)
+	users.Delete("/:id", h.DeleteUser)
+
+	router.Post("/utils/normalize", h.NormalizeContacts)
+}
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
 	"kbtg.tech/ai-backend-workshop/internal/mocks"
 )
@@ -62,6 +66,43 @@ func TestUserHandler_GetUsers_Error(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestUserHandler_GetUsers_PaginationLinks(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	users := []domain.User{{ID: 3}, {ID: 4}}
+	mockUseCase.On("ListUsers", domain.UserListOptions{Page: 2, Limit: 2, SortBy: "points"}).Return(users, int64(5), nil)
+	mockUseCase.On("ListUsers", domain.UserListOptions{Page: 3, Limit: 2, SortBy: "points"}).Return(users[:1], int64(5), nil)
+
+	app.Get("/users", handler.GetUsers)
+
+	// Act
+	middle, err := app.Test(httptest.NewRequest("GET", "/users?page=2&limit=2&sort=points", nil))
+	assert.NoError(t, err)
+	last, err := app.Test(httptest.NewRequest("GET", "/users?page=3&limit=2&sort=points", nil))
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 200, middle.StatusCode)
+	middleLinks := middle.Header.Get("Link")
+	assert.Contains(t, middleLinks, `<http://example.com/users?limit=2&page=3&sort=points>; rel="next"`)
+	assert.Contains(t, middleLinks, `<http://example.com/users?limit=2&page=1&sort=points>; rel="prev"`)
+	assert.Contains(t, middleLinks, `<http://example.com/users?limit=2&page=3&sort=points>; rel="last"`)
+
+	assert.Equal(t, 200, last.StatusCode)
+	lastLinks := last.Header.Get("Link")
+	assert.NotContains(t, lastLinks, `rel="next"`)
+	assert.Contains(t, lastLinks, `<http://example.com/users?limit=2&page=1&sort=points>; rel="first"`)
+
+	var response map[string]interface{}
+	assert.NoError(t, json.NewDecoder(middle.Body).Decode(&response))
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(3), pagination["total_pages"])
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUserHandler_GetUser(t *testing.T) {
 	// Arrange
 	mockUseCase := new(mocks.MockUserUseCase)
@@ -113,7 +154,7 @@ func TestUserHandler_GetUser_NotFound(t *testing.T) {
 	handler := NewUserHandler(mockUseCase)
 	app := setupTestApp()
 
-	mockUseCase.On("GetUserByID", uint(1)).Return(nil, errors.New("user not found"))
+	mockUseCase.On("GetUserByID", uint(1)).Return(nil, domain.ErrUserNotFound)
 
 	app.Get("/users/:id", handler.GetUser)
 
@@ -127,6 +168,40 @@ func TestUserHandler_GetUser_NotFound(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestUserHandler_GetUserByMembershipID(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	mockUseCase.On("GetUserByMembershipID", "LBK001234").Return(&domain.User{ID: 1, MembershipID: "LBK001234"}, nil)
+	mockUseCase.On("GetUserByMembershipID", "LBK999999").Return(nil, domain.ErrUserNotFound)
+	mockUseCase.On("GetUserByMembershipID", "%20").Return(nil, domain.ErrInvalidMembershipID)
+
+	app.Get("/users/by-membership/:code", handler.GetUserByMembershipID)
+
+	// Act
+	found, err := app.Test(httptest.NewRequest("GET", "/users/by-membership/LBK001234", nil))
+	assert.NoError(t, err)
+	missing, err := app.Test(httptest.NewRequest("GET", "/users/by-membership/LBK999999", nil))
+	assert.NoError(t, err)
+	blank, err := app.Test(httptest.NewRequest("GET", "/users/by-membership/%20", nil))
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 200, found.StatusCode)
+	var response map[string]domain.User
+	assert.NoError(t, json.NewDecoder(found.Body).Decode(&response))
+	assert.Equal(t, uint(1), response["data"].ID)
+
+	assert.Equal(t, 404, missing.StatusCode)
+	var errResponse errorResponse
+	assert.NoError(t, json.NewDecoder(missing.Body).Decode(&errResponse))
+	assert.Equal(t, ErrorBody{Code: "USER_NOT_FOUND", Message: "User not found"}, errResponse.Error)
+	assert.Equal(t, 400, blank.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUserHandler_CreateUser(t *testing.T) {
 	// Arrange
 	mockUseCase := new(mocks.MockUserUseCase)
@@ -199,7 +274,9 @@ func TestUserHandler_CreateUser_ValidationError(t *testing.T) {
 		Email: "john@example.com",
 	}
 
-	mockUseCase.On("CreateUser", createReq).Return(nil, errors.New("first name, last name, and email are required"))
+	mockUseCase.On("CreateUser", createReq).Return(nil, &domain.ValidationError{
+		Fields: []domain.FieldError{{Field: "last_name", Message: "is required"}},
+	})
 
 	app.Post("/users", handler.CreateUser)
 
@@ -212,6 +289,47 @@ func TestUserHandler_CreateUser_ValidationError(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, 400, resp.StatusCode)
+
+	var response errorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, ErrorBody{
+		Code:    "VALIDATION_FAILED",
+		Message: "validation failed",
+		Details: []domain.FieldError{{Field: "last_name", Message: "is required"}},
+	}, response.Error)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestUserHandler_CreateUser_CouldNotGenerateID(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	createReq := domain.CreateUserRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	}
+
+	mockUseCase.On("CreateUser", createReq).Return(nil, domain.ErrCouldNotGenerateID)
+
+	app.Post("/users", handler.CreateUser)
+
+	// Act
+	body, _ := json.Marshal(createReq)
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	var response errorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, "MEMBERSHIP_ID_UNAVAILABLE", response.Error.Code)
+	assert.Contains(t, response.Error.Message, "unique membership ID")
 	mockUseCase.AssertExpectations(t)
 }
 
@@ -221,9 +339,10 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 	handler := NewUserHandler(mockUseCase)
 	app := setupTestApp()
 
+	points := 200
 	updateReq := domain.UpdateUserRequest{
 		FirstName: "Jane",
-		Points:    200,
+		Points:    &points,
 	}
 
 	expectedUser := &domain.User{
@@ -250,6 +369,30 @@ func TestUserHandler_UpdateUser(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestUserHandler_UpdateUser_TierTransitionNotAllowed(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	updateReq := domain.UpdateUserRequest{MembershipType: "Gold"}
+
+	mockUseCase.On("UpdateUser", uint(1), updateReq).Return(nil, fmt.Errorf("%w: Bronze to Gold", domain.ErrTierTransitionNotAllowed))
+
+	app.Put("/users/:id", handler.UpdateUser)
+
+	// Act
+	body, _ := json.Marshal(updateReq)
+	req := httptest.NewRequest("PUT", "/users/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestUserHandler_DeleteUser(t *testing.T) {
 	// Arrange
 	mockUseCase := new(mocks.MockUserUseCase)
@@ -276,7 +419,7 @@ func TestUserHandler_DeleteUser_NotFound(t *testing.T) {
 	handler := NewUserHandler(mockUseCase)
 	app := setupTestApp()
 
-	mockUseCase.On("DeleteUser", uint(1)).Return(errors.New("user not found"))
+	mockUseCase.On("DeleteUser", uint(1)).Return(domain.ErrUserNotFound)
 
 	app.Delete("/users/:id", handler.DeleteUser)
 
@@ -289,3 +432,134 @@ func TestUserHandler_DeleteUser_NotFound(t *testing.T) {
 	assert.Equal(t, 404, resp.StatusCode)
 	mockUseCase.AssertExpectations(t)
 }
+
+func TestUserHandler_DeleteUser_IfMatch(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	user := &domain.User{ID: 1, FirstName: "John", UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	mockUseCase.On("DeleteUserIfUnmodified", uint(1), []time.Time{time.Unix(0, user.UpdatedAt.UnixNano())}).Return(nil)
+
+	app.Delete("/users/:id", handler.DeleteUser)
+
+	// Act
+	req := httptest.NewRequest("DELETE", "/users/1", nil)
+	req.Header.Set("If-Match", user.ETag())
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestUserHandler_DeleteUser_IfMatchMismatch(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	mockUseCase.On("DeleteUserIfUnmodified", uint(1), []time.Time{time.Unix(0, 0)}).Return(domain.ErrUserModified)
+
+	app.Delete("/users/:id", handler.DeleteUser)
+
+	// Act
+	req := httptest.NewRequest("DELETE", "/users/1", nil)
+	req.Header.Set("If-Match", `"1-0"`)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 412, resp.StatusCode)
+	mockUseCase.AssertNotCalled(t, "DeleteUser", uint(1))
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestUserHandler_DeleteUsers_Error(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	mockUseCase.On("DeleteUsers", []uint{1, 2}).Return(nil, errors.New("database error"))
+
+	app.Post("/users/batch-delete", handler.DeleteUsers)
+
+	// Act
+	req := httptest.NewRequest("POST", "/users/batch-delete", bytes.NewBufferString(`{"ids":[1,2]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestUserHandler_GetImportTemplate(t *testing.T) {
+	// Arrange
+	mockUseCase := new(mocks.MockUserUseCase)
+	handler := NewUserHandler(mockUseCase)
+	app := setupTestApp()
+
+	app.Get("/users/import/template.csv", handler.GetImportTemplate)
+
+	// Act
+	req := httptest.NewRequest("GET", "/users/import/template.csv", nil)
+	resp, err := app.Test(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/csv")
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{domain.UserCSVColumns}, records)
+}
+
+func TestUserHandler_EmptyBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		error  string
+	}{
+		{"create empty", "POST", "/users", "", "request body is required"},
+		{"create whitespace", "POST", "/users", "  \n", "request body is required"},
+		{"create malformed", "POST", "/users", "{invalid", "Invalid request body"},
+		{"update empty", "PUT", "/users/1", "", "request body is required"},
+		{"update malformed", "PUT", "/users/1", "{invalid", "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockUseCase := new(mocks.MockUserUseCase)
+			handler := NewUserHandler(mockUseCase)
+			app := setupTestApp()
+
+			app.Post("/users", handler.CreateUser)
+			app.Put("/users/:id", handler.UpdateUser)
+
+			// Act
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, 400, resp.StatusCode)
+
+			var response errorResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, tt.error, response.Error.Message)
+			mockUseCase.AssertNotCalled(t, "CreateUser", mock.Anything)
+			mockUseCase.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+		})
+	}
+}
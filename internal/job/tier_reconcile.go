@@ -0,0 +1,39 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// RunTierReconciliation checks stored tiers against points every interval until ctx
+// is done, logging each mismatch found
+func RunTierReconciliation(ctx context.Context, userUseCase domain.UserUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ReconcileTiersOnce(userUseCase)
+		}
+	}
+}
+
+// ReconcileTiersOnce runs a single reconciliation pass and logs the outcome
+func ReconcileTiersOnce(userUseCase domain.UserUseCase) []domain.TierMismatch {
+	mismatches, err := userUseCase.ReconcileTiers()
+	if err != nil {
+		log.Printf("tier reconciliation failed: %v", err)
+	}
+	for _, m := range mismatches {
+		log.Printf("tier mismatch: user=%d points=%d stored=%s expected=%s corrected=%t",
+			m.UserID, m.Points, m.Stored, m.Expected, m.Corrected)
+	}
+	log.Printf("tier reconciliation: %d mismatched users", len(mismatches))
+	return mismatches
+}
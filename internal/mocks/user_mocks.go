@@ -1,6 +1,10 @@
 package mocks
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/stretchr/testify/mock"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
 )
@@ -15,6 +19,21 @@ func (m *MockUserRepository) GetAll() ([]domain.User, error) {
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) List(opts domain.UserListOptions) ([]domain.User, int64, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Search(query string, opts domain.UserListOptions) ([]domain.User, int64, error) {
+	args := m.Called(query, opts)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) ListChangedSince(since time.Time) ([]domain.User, error) {
+	args := m.Called(since)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByID(id uint) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -23,6 +42,14 @@ func (m *MockUserRepository) GetByID(id uint) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDFromPrimary(id uint) (*domain.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByEmail(email string) (*domain.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
@@ -31,13 +58,114 @@ func (m *MockUserRepository) GetByEmail(email string) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Create(user *domain.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) EmailInUse(email string, includeDeleted bool) (bool, error) {
+	args := m.Called(email, includeDeleted)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByMembershipID(membershipID string) (*domain.User, error) {
+	args := m.Called(membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByMembershipIDs(membershipIDs []string) ([]domain.User, error) {
+	args := m.Called(membershipIDs)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindExistingEmails(emails []string, includeDeleted bool) ([]string, error) {
+	args := m.Called(emails, includeDeleted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// WithTransaction runs fn against the mock itself; expectations set on the mock
+// apply to the operations made inside the transaction
+func (m *MockUserRepository) WithTransaction(fn func(txRepo domain.UserRepository) error) error {
+	return fn(m)
+}
+
+func (m *MockUserRepository) Create(user *domain.User, welcomeBonus int) error {
+	args := m.Called(user, welcomeBonus)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Update(user *domain.User) error {
-	args := m.Called(user)
+func (m *MockUserRepository) Update(user *domain.User, guard domain.PointsGuard) error {
+	args := m.Called(user, guard)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateMembershipID(id uint, membershipID string) error {
+	args := m.Called(id, membershipID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ReplaceMembershipID(id uint, membershipID string) (*domain.MembershipIDChange, error) {
+	args := m.Called(id, membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MembershipIDChange), args.Error(1)
+}
+
+func (m *MockUserRepository) IsMembershipIDRevoked(membershipID string) (bool, error) {
+	args := m.Called(membershipID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) MembershipIDInUse(membershipID string) (bool, error) {
+	args := m.Called(membershipID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) SetPoints(id uint, points int, tiers domain.TierPolicy, reason string, guard domain.PointsGuard) (*domain.User, *domain.PointsTransaction, error) {
+	args := m.Called(id, points, tiers, reason, guard)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.User), args.Get(1).(*domain.PointsTransaction), args.Error(2)
+}
+
+func (m *MockUserRepository) AddPoints(id uint, delta int, tiers domain.TierPolicy, guard domain.PointsGuard) error {
+	args := m.Called(id, delta, tiers, guard)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetMembershipType(id uint, membershipType, trigger string) error {
+	args := m.Called(id, membershipType, trigger)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateStatus(id uint, status string) error {
+	args := m.Called(id, status)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdjustPoints(adjustments []domain.PointsAdjustment, tiers domain.TierPolicy, guard domain.PointsGuard) error {
+	args := m.Called(adjustments, tiers, guard)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetTierHistory(userID uint) ([]domain.TierChange, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierChange), args.Error(1)
+}
+
+func (m *MockUserRepository) SumPointsChange(userID uint, since time.Time) (int64, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) DeleteIfUnmodified(id uint, revisions []time.Time) error {
+	args := m.Called(id, revisions)
 	return args.Error(0)
 }
 
@@ -46,6 +174,86 @@ func (m *MockUserRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) Restore(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteByIDs(ids []uint) ([]uint, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockUserRepository) SumPoints(membershipType string) (int64, error) {
+	args := m.Called(membershipType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) GetTierStats(membershipType string) ([]domain.TierStats, error) {
+	args := m.Called(membershipType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierStats), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserStats(membershipType string) (*domain.UserStats, error) {
+	args := m.Called(membershipType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserStats), args.Error(1)
+}
+
+func (m *MockUserRepository) GetIdempotencyKey(key string, since time.Time) (*domain.IdempotencyKey, error) {
+	args := m.Called(key, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateWithIdempotencyKey(user *domain.User, welcomeBonus int, key *domain.IdempotencyKey, expiredBefore time.Time) error {
+	args := m.Called(user, welcomeBonus, key, expiredBefore)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CountTierMoves(thresholds domain.TierThresholds) ([]domain.TierMove, error) {
+	args := m.Called(thresholds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierMove), args.Error(1)
+}
+
+func (m *MockUserRepository) GetTopByPoints(opts domain.LeaderboardOptions) ([]domain.User, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) CountWithMorePoints(points int) (int64, error) {
+	args := m.Called(points)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) CountJoinedSince(since time.Time) (int64, error) {
+	args := m.Called(since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockPointsTransactionRepository is a mock implementation of domain.PointsTransactionRepository
+type MockPointsTransactionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPointsTransactionRepository) ListByUser(userID uint) ([]domain.PointsTransaction, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]domain.PointsTransaction), args.Error(1)
+}
+
 // MockUserUseCase is a mock implementation of domain.UserUseCase
 type MockUserUseCase struct {
 	mock.Mock
@@ -56,6 +264,21 @@ func (m *MockUserUseCase) GetAllUsers() ([]domain.User, error) {
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
+func (m *MockUserUseCase) ListUsers(opts domain.UserListOptions) ([]domain.User, int64, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserUseCase) SearchUsers(query string, opts domain.UserListOptions) ([]domain.User, int64, error) {
+	args := m.Called(query, opts)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserUseCase) GetUserChanges(since time.Time) ([]domain.UserChange, time.Time, error) {
+	args := m.Called(since)
+	return args.Get(0).([]domain.UserChange), args.Get(1).(time.Time), args.Error(2)
+}
+
 func (m *MockUserUseCase) GetUserByID(id uint) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -64,6 +287,27 @@ func (m *MockUserUseCase) GetUserByID(id uint) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserUseCase) GetUserByMembershipID(membershipID string) (*domain.User, error) {
+	args := m.Called(membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetUsersByMembershipIDs(membershipIDs []string) ([]domain.User, []string, error) {
+	args := m.Called(membershipIDs)
+	return args.Get(0).([]domain.User), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockUserUseCase) NormalizeContacts(req domain.NormalizeRequest) (*domain.NormalizeResult, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NormalizeResult), args.Error(1)
+}
+
 func (m *MockUserUseCase) CreateUser(req domain.CreateUserRequest) (*domain.User, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
@@ -72,6 +316,14 @@ func (m *MockUserUseCase) CreateUser(req domain.CreateUserRequest) (*domain.User
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserUseCase) CreateUserIdempotent(key string, req domain.CreateUserRequest) (*domain.User, bool, error) {
+	args := m.Called(key, req)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*domain.User), args.Bool(1), args.Error(2)
+}
+
 func (m *MockUserUseCase) UpdateUser(id uint, req domain.UpdateUserRequest) (*domain.User, error) {
 	args := m.Called(id, req)
 	if args.Get(0) == nil {
@@ -80,7 +332,186 @@ func (m *MockUserUseCase) UpdateUser(id uint, req domain.UpdateUserRequest) (*do
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserUseCase) SetPoints(id uint, points int) (*domain.User, *domain.PointsTransaction, error) {
+	args := m.Called(id, points)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.User), args.Get(1).(*domain.PointsTransaction), args.Error(2)
+}
+
+func (m *MockUserUseCase) AddPoints(id uint, delta int) (*domain.User, error) {
+	args := m.Called(id, delta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) ResetUser(id uint) (*domain.User, *domain.PointsTransaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.User), args.Get(1).(*domain.PointsTransaction), args.Error(2)
+}
+
+func (m *MockUserUseCase) GetTierHistory(id uint) ([]domain.TierChange, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierChange), args.Error(1)
+}
+
+func (m *MockUserUseCase) DeleteUserIfUnmodified(id uint, revisions []time.Time) error {
+	args := m.Called(id, revisions)
+	return args.Error(0)
+}
+
 func (m *MockUserUseCase) DeleteUser(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+func (m *MockUserUseCase) RestoreUser(id uint) (*domain.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) UpdateStatus(id uint, status string) (*domain.User, error) {
+	args := m.Called(id, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) DeleteUsers(ids []uint) ([]domain.BatchDeleteResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BatchDeleteResult), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetTotalPoints(membershipType string) (int64, error) {
+	args := m.Called(membershipType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetLeaderboard(opts domain.LeaderboardOptions) ([]domain.LeaderboardEntry, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]domain.LeaderboardEntry), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetDashboard() (*domain.Dashboard, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Dashboard), args.Error(1)
+}
+
+func (m *MockUserUseCase) ReissueInvalidMembershipIDs() ([]domain.MembershipIDChange, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.MembershipIDChange), args.Error(1)
+}
+
+func (m *MockUserUseCase) ReissueMembershipID(id uint) (*domain.MembershipIDChange, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MembershipIDChange), args.Error(1)
+}
+
+func (m *MockUserUseCase) VerifyMembershipID(membershipID string) (*domain.User, error) {
+	args := m.Called(membershipID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserUseCase) ComputeFields(user *domain.User, fields []string) (map[string]int, error) {
+	args := m.Called(user, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockUserUseCase) ImportUsersFromURL(ctx context.Context, rawURL string) ([]domain.ImportRowResult, error) {
+	args := m.Called(ctx, rawURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ImportRowResult), args.Error(1)
+}
+
+func (m *MockUserUseCase) ImportPoints(r io.Reader, mode string) ([]domain.ImportRowResult, error) {
+	args := m.Called(r, mode)
+	return args.Get(0).([]domain.ImportRowResult), args.Error(1)
+}
+
+func (m *MockUserUseCase) CheckEmailsExist(emails []string) (map[string]bool, error) {
+	args := m.Called(emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockUserUseCase) FindDuplicateCandidates() ([]domain.DuplicateCandidate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DuplicateCandidate), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetTierStats(membershipType string) ([]domain.TierStats, error) {
+	args := m.Called(membershipType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierStats), args.Error(1)
+}
+
+func (m *MockUserUseCase) GetUserStats(membershipType string) (*domain.UserStats, error) {
+	args := m.Called(membershipType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserStats), args.Error(1)
+}
+
+func (m *MockUserUseCase) ReconcileTiers() ([]domain.TierMismatch, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierMismatch), args.Error(1)
+}
+
+func (m *MockUserUseCase) SimulateTiers(thresholds domain.TierThresholds) ([]domain.TierSimulation, error) {
+	args := m.Called(thresholds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TierSimulation), args.Error(1)
+}
+
+// MockNotifier is a mock implementation of domain.Notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) SendWelcome(user *domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/pkg/database"
+)
+
+// pointsTransactionRepository implements the PointsTransactionRepository interface
+type pointsTransactionRepository struct {
+	db *database.DB
+}
+
+// NewPointsTransactionRepository creates a new points transaction repository
+func NewPointsTransactionRepository(db *database.DB) domain.PointsTransactionRepository {
+	return &pointsTransactionRepository{
+		db: db,
+	}
+}
+
+// ListByUser retrieves a user's points transactions, oldest first
+func (r *pointsTransactionRepository) ListByUser(userID uint) ([]domain.PointsTransaction, error) {
+	var txns []domain.PointsTransaction
+	if err := r.db.Reader().Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&txns).Error; err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
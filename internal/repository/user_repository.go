@@ -2,8 +2,11 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
 	"kbtg.tech/ai-backend-workshop/pkg/database"
 )
@@ -20,10 +23,119 @@ func NewUserRepository(db *database.DB) domain.UserRepository {
 	}
 }
 
+// WithTransaction runs fn with a repository bound to one transaction, so every
+// operation fn makes through it commits or rolls back together
+func (r *userRepository) WithTransaction(fn func(txRepo domain.UserRepository) error) error {
+	return r.db.InTransaction(func(tx *database.DB) error {
+		return fn(&userRepository{db: tx})
+	})
+}
+
 // GetAll retrieves all users from the database
 func (r *userRepository) GetAll() ([]domain.User, error) {
 	var users []domain.User
-	if err := r.db.Find(&users).Error; err != nil {
+	if err := r.db.Reader().Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// List retrieves one page of users, optionally filtered by membership type,
+// along with the total number of matching users
+func (r *userRepository) List(opts domain.UserListOptions) ([]domain.User, int64, error) {
+	filtered := r.db.Reader().Model(&domain.User{})
+	if opts.IncludeDeleted {
+		filtered = filtered.Unscoped()
+	}
+	if len(opts.MembershipTypes) > 0 {
+		filtered = filtered.Where("membership_type IN ?", opts.MembershipTypes)
+	}
+	if opts.Status != "" {
+		filtered = filtered.Where("status = ?", opts.Status)
+	}
+	if opts.AfterID > 0 {
+		filtered = filtered.Where("id > ?", opts.AfterID)
+	}
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	query := filtered.Session(&gorm.Session{})
+	if opts.SortBy != "" && opts.SortBy != "id" {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: opts.SortBy},
+			Desc:   opts.Order == domain.SortOrderDesc,
+		})
+	}
+	query = query.Order(clause.OrderByColumn{
+		Column: clause.Column{Name: "id"},
+		Desc:   opts.SortBy == "id" && opts.Order == domain.SortOrderDesc,
+	})
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit).Offset((opts.Page - 1) * opts.Limit)
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// updatableUserColumns lists the columns Update writes
+var updatableUserColumns = []string{"first_name", "last_name", "email", "email_hash", "phone", "membership_type", "points", "updated_at"}
+
+// likeEscaper escapes the LIKE wildcards in user-supplied search text
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Search retrieves one page of users whose first name, last name or email
+// contains query, ignoring case, along with the total number of matches.
+// While PII encryption is on, emails only match in full.
+func (r *userRepository) Search(query string, opts domain.UserListOptions) ([]domain.User, int64, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	matched := r.db.Reader().Model(&domain.User{})
+	if r.db.PII != nil {
+		matched = matched.Where(
+			`LOWER(first_name) LIKE ? ESCAPE '\' OR LOWER(last_name) LIKE ? ESCAPE '\' OR email_hash = ?`,
+			pattern, pattern, r.db.HashEmail(query),
+		)
+	} else {
+		matched = matched.Where(
+			`LOWER(first_name) LIKE ? ESCAPE '\' OR LOWER(last_name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern,
+		)
+	}
+
+	var total int64
+	if err := matched.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err := matched.Session(&gorm.Session{}).
+		Order("id ASC").
+		Limit(opts.Limit).
+		Offset((opts.Page - 1) * opts.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// ListChangedSince retrieves the users updated or soft-deleted after since,
+// including deleted users, ordered by when they last changed
+func (r *userRepository) ListChangedSince(since time.Time) ([]domain.User, error) {
+	// Timestamps are stored in local time and compared as text
+	since = since.Local()
+
+	var users []domain.User
+	err := r.db.Reader().Unscoped().
+		Where("updated_at > ? OR deleted_at > ?", since, since).
+		Order("CASE WHEN deleted_at > updated_at THEN deleted_at ELSE updated_at END ASC, id ASC").
+		Find(&users).Error
+	if err != nil {
 		return nil, err
 	}
 	return users, nil
@@ -31,46 +143,703 @@ func (r *userRepository) GetAll() ([]domain.User, error) {
 
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(id uint) (*domain.User, error) {
+	return r.getByID(r.db.Reader(), id)
+}
+
+// GetByIDFromPrimary retrieves a user by ID. It reads the primary so callers
+// about to write the row, or returning it right after a write, see the latest
+// version rather than a lagging replica.
+func (r *userRepository) GetByIDFromPrimary(id uint) (*domain.User, error) {
+	return r.getByID(r.db.DB, id)
+}
+
+func (r *userRepository) getByID(tx *gorm.DB, id uint) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := tx.First(&user, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
+// emailCondition returns a where clause matching users by email, comparing the
+// email hash while PII encryption is on
+func (r *userRepository) emailCondition(email string) (string, string) {
+	if r.db.PII != nil {
+		return "email_hash = ?", r.db.HashEmail(email)
+	}
+	return "email = ?", email
+}
+
 // GetByEmail retrieves a user by email
 func (r *userRepository) GetByEmail(email string) (*domain.User, error) {
 	var user domain.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	query, value := r.emailCondition(email)
+	if err := r.db.Where(query, value).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Create creates a new user in the database
-func (r *userRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+// EmailInUse reports whether a user holds the email, counting soft-deleted users when includeDeleted is set
+func (r *userRepository) EmailInUse(email string, includeDeleted bool) (bool, error) {
+	tx := r.db.Model(&domain.User{})
+	if includeDeleted {
+		tx = tx.Unscoped()
+	}
+
+	var count int64
+	query, value := r.emailCondition(email)
+	if err := tx.Where(query, value).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
-// Update updates an existing user in the database
-func (r *userRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+// GetByMembershipID retrieves a user by membership ID with a single lookup on its unique index
+func (r *userRepository) GetByMembershipID(membershipID string) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.Where("membership_id = ?", membershipID).Take(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByMembershipIDs retrieves the users holding any of the given membership IDs in one query
+func (r *userRepository) GetByMembershipIDs(membershipIDs []string) ([]domain.User, error) {
+	var users []domain.User
+	if err := r.db.Reader().Where("membership_id IN ?", membershipIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
 }
 
-// Delete deletes a user by ID
+// FindExistingEmails returns which of the given lowercase emails are registered, compared
+// case-insensitively, counting soft-deleted users when includeDeleted is set
+func (r *userRepository) FindExistingEmails(emails []string, includeDeleted bool) ([]string, error) {
+	tx := r.db.Reader().Model(&domain.User{})
+	if includeDeleted {
+		tx = tx.Unscoped()
+	}
+	if r.db.PII != nil {
+		return r.findExistingEmailHashes(tx, emails)
+	}
+
+	var found []string
+	if err := tx.Where("LOWER(email) IN ?", emails).Pluck("LOWER(email)", &found).Error; err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// findExistingEmailHashes is FindExistingEmails for encrypted emails, matching on the email hash within tx
+func (r *userRepository) findExistingEmailHashes(tx *gorm.DB, emails []string) ([]string, error) {
+	byHash := make(map[string]string, len(emails))
+	hashes := make([]string, 0, len(emails))
+	for _, email := range emails {
+		hash := r.db.HashEmail(email)
+		byHash[hash] = email
+		hashes = append(hashes, hash)
+	}
+
+	var foundHashes []string
+	if err := tx.Where("email_hash IN ?", hashes).Pluck("email_hash", &foundHashes).Error; err != nil {
+		return nil, err
+	}
+	found := make([]string, len(foundHashes))
+	for i, hash := range foundHashes {
+		found[i] = byHash[hash]
+	}
+	return found, nil
+}
+
+// Create creates a new user in the database, opening the points ledger with
+// the starting balance in the same transaction. welcomeBonus is the part of the
+// balance granted on signup; it is recorded as a transaction of its own.
+func (r *userRepository) Create(user *domain.User, welcomeBonus int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return r.createUser(tx, user, welcomeBonus)
+	})
+}
+
+// createUser inserts user and opens its points ledger within tx, recording the
+// starting balance and welcomeBonus separately
+func (r *userRepository) createUser(tx *gorm.DB, user *domain.User, welcomeBonus int) error {
+	user.EmailHash = r.db.HashEmail(user.Email)
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	initial := user.Points - welcomeBonus
+	if err := recordPointsChange(tx, user.ID, 0, initial, domain.PointsReasonInitial); err != nil {
+		return err
+	}
+	return recordPointsChange(tx, user.ID, initial, user.Points, domain.PointsReasonWelcomeBonus)
+}
+
+// GetIdempotencyKey returns the idempotency key recorded since the given time,
+// or nil when the key is unknown or older. It reads the primary so a retry
+// sees the key its first attempt just wrote.
+func (r *userRepository) GetIdempotencyKey(key string, since time.Time) (*domain.IdempotencyKey, error) {
+	var found []domain.IdempotencyKey
+	if err := r.db.Where("idempotency_key = ? AND created_at >= ?", key, since).Limit(1).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return &found[0], nil
+}
+
+// CreateWithIdempotencyKey creates user, as Create does, and records key for it in one
+// transaction, first purging keys created before expiredBefore. A key that is
+// still recorded fails the insert, so concurrent retries create one user.
+func (r *userRepository) CreateWithIdempotencyKey(user *domain.User, welcomeBonus int, key *domain.IdempotencyKey, expiredBefore time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("created_at < ?", expiredBefore).Delete(&domain.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		if err := r.createUser(tx, user, welcomeBonus); err != nil {
+			return err
+		}
+		key.UserID = user.ID
+		return tx.Create(key).Error
+	})
+}
+
+// Update updates an existing user in the database, recording a tier change and
+// a points transaction in the same transaction when they differ from the stored ones.
+// A points change is checked with guard against the stored balance, which may
+// reduce it to stop at the tier's floor, and against the rate limit.
+// Only the profile, tier and points columns are written, so a concurrent status
+// change or membership ID reissue is not overwritten with the caller's stale copy.
+func (r *userRepository) Update(user *domain.User, guard domain.PointsGuard) error {
+	user.EmailHash = r.db.HashEmail(user.Email)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous []domain.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("membership_type", "points", "status").Where("id = ?", user.ID).Limit(1).Find(&previous).Error; err != nil {
+			return err
+		}
+		if len(previous) > 0 && user.Points != previous[0].Points {
+			if err := checkNotSuspended(&previous[0]); err != nil {
+				return err
+			}
+			delta, err := guard.Apply(previous[0].MembershipType, previous[0].Points, user.Points-previous[0].Points)
+			if err != nil {
+				return err
+			}
+			if err := checkPointsRate(tx, user.ID, delta, guard); err != nil {
+				return err
+			}
+			user.Points = previous[0].Points + delta
+		}
+		if len(previous) == 0 {
+			return domain.ErrUserNotFound
+		}
+		if err := tx.Model(user).Select(updatableUserColumns).Updates(user).Error; err != nil {
+			return err
+		}
+		if err := recordPointsChange(tx, user.ID, previous[0].Points, user.Points, domain.PointsReasonUpdate); err != nil {
+			return err
+		}
+		return recordTierChange(tx, user.ID, previous[0].MembershipType, user.MembershipType, domain.TierTriggerUpdate)
+	})
+}
+
+// checkNotSuspended rejects changing the points of a suspended user
+func checkNotSuspended(user *domain.User) error {
+	if user.Status == domain.UserStatusSuspended {
+		return domain.ErrUserSuspended
+	}
+	return nil
+}
+
+// checkPointsRate rejects moving a user's balance by delta when the total moved
+// within guard's window, read in tx, would exceed its rate limit
+func checkPointsRate(tx *gorm.DB, userID uint, delta int, guard domain.PointsGuard) error {
+	if guard.RateLimit <= 0 {
+		return nil
+	}
+	moved, err := sumPointsChange(tx, userID, time.Now().Add(-guard.RateWindow))
+	if err != nil {
+		return err
+	}
+	return guard.CheckRate(moved, delta)
+}
+
+// recordPointsChange stores a points transaction when the balance moves from from to to
+func recordPointsChange(tx *gorm.DB, userID uint, from, to int, reason string) error {
+	if from == to {
+		return nil
+	}
+	return tx.Create(&domain.PointsTransaction{UserID: userID, Delta: to - from, Balance: to, Reason: reason}).Error
+}
+
+// recordTierChange stores a tier change when from and to differ
+func recordTierChange(tx *gorm.DB, userID uint, from, to, trigger string) error {
+	if from == to {
+		return nil
+	}
+	return tx.Create(&domain.TierChange{UserID: userID, From: from, To: to, Trigger: trigger}).Error
+}
+
+// UpdateMembershipID replaces the membership ID of a user
+func (r *userRepository) UpdateMembershipID(id uint, membershipID string) error {
+	result := r.db.Model(&domain.User{}).Where("id = ?", id).Update("membership_id", membershipID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// ReplaceMembershipID gives a user a new membership ID and revokes the old one
+// in the same transaction
+func (r *userRepository) ReplaceMembershipID(id uint, membershipID string) (*domain.MembershipIDChange, error) {
+	var change domain.MembershipIDChange
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		change = domain.MembershipIDChange{UserID: user.ID, OldID: user.MembershipID, NewID: membershipID}
+		if err := tx.Create(&domain.RevokedMembershipID{MembershipID: user.MembershipID, UserID: user.ID}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("membership_id", membershipID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// IsMembershipIDRevoked reports whether a membership ID was revoked by a card reissue
+func (r *userRepository) IsMembershipIDRevoked(membershipID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&domain.RevokedMembershipID{}).Where("membership_id = ?", membershipID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// MembershipIDInUse reports whether a membership ID may not be issued: a user
+// holds it, soft-deleted users included, or a card reissue revoked it
+func (r *userRepository) MembershipIDInUse(membershipID string) (bool, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&domain.User{}).Where("membership_id = ?", membershipID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	return r.IsMembershipIDRevoked(membershipID)
+}
+
+// SetPoints sets a user's points balance, moves them to the tier tiers decides on
+// and records the change as a transaction with the given reason, all in one
+// database transaction. The change is checked against guard's rate limit in the
+// same transaction.
+func (r *userRepository) SetPoints(id uint, points int, tiers domain.TierPolicy, reason string, guard domain.PointsGuard) (*domain.User, *domain.PointsTransaction, error) {
+	var user domain.User
+	var txn domain.PointsTransaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+		if err := checkNotSuspended(&user); err != nil {
+			return err
+		}
+		if err := checkPointsRate(tx, user.ID, points-user.Points, guard); err != nil {
+			return err
+		}
+
+		previousTier := user.MembershipType
+		txn = domain.PointsTransaction{
+			UserID:  user.ID,
+			Delta:   points - user.Points,
+			Balance: points,
+			Reason:  reason,
+		}
+		user.Points = points
+		user.MembershipType = tiers.TierAfter(previousTier, points)
+
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if err := recordTierChange(tx, user.ID, previousTier, user.MembershipType, reason); err != nil {
+			return err
+		}
+		return tx.Create(&txn).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &user, &txn, nil
+}
+
+// AddPoints moves a user's balance by delta with a single conditional UPDATE, so
+// concurrent calls cannot lose increments or drive the balance below zero or the
+// tier's floor in guard, nor together move it past guard's rate limit. The tier,
+// decided by tiers, and ledger entry are updated in the same transaction.
+func (r *userRepository) AddPoints(id uint, delta int, tiers domain.TierPolicy, guard domain.PointsGuard) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+		if err := checkNotSuspended(&user); err != nil {
+			return err
+		}
+		delta, err := guard.Apply(user.MembershipType, user.Points, delta)
+		if err != nil {
+			return err
+		}
+		if user.Points+delta < 0 {
+			return domain.ErrNegativePoints
+		}
+		if err := checkPointsRate(tx, user.ID, delta, guard); err != nil {
+			return err
+		}
+
+		result := tx.Model(&domain.User{}).
+			Where("id = ? AND membership_type = ? AND points + ? >= ?", id, user.MembershipType, delta, guard.MinBalance(user.MembershipType, delta)).
+			Update("points", gorm.Expr("points + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrUserModified
+		}
+		user.Points += delta
+
+		previousTier := user.MembershipType
+		if tier := tiers.TierAfter(previousTier, user.Points); tier != previousTier {
+			if err := tx.Model(&user).Update("membership_type", tier).Error; err != nil {
+				return err
+			}
+			if err := recordTierChange(tx, user.ID, previousTier, tier, domain.PointsReasonAdd); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&domain.PointsTransaction{
+			UserID:  user.ID,
+			Delta:   delta,
+			Balance: user.Points,
+			Reason:  domain.PointsReasonAdd,
+		}).Error
+	})
+}
+
+// SetMembershipType changes a user's tier and records the change with the given trigger
+func (r *userRepository) SetMembershipType(id uint, membershipType, trigger string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+		previous := user.MembershipType
+		if err := tx.Model(&user).Update("membership_type", membershipType).Error; err != nil {
+			return err
+		}
+		return recordTierChange(tx, id, previous, membershipType, trigger)
+	})
+}
+
+// UpdateStatus sets the account status of a user
+func (r *userRepository) UpdateStatus(id uint, status string) error {
+	result := r.db.Model(&domain.User{}).Where("id = ?", id).Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// AdjustPoints applies points adjustments in order within one transaction, moving
+// each user to the tier tiers decides on for their new balance. No adjustment is kept
+// if any would take a balance below zero or, unless clamped, below the tier's floor in guard,
+// or move it past guard's rate limit.
+func (r *userRepository) AdjustPoints(adjustments []domain.PointsAdjustment, tiers domain.TierPolicy, guard domain.PointsGuard) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, adjustment := range adjustments {
+			var user domain.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, adjustment.UserID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrUserNotFound
+				}
+				return err
+			}
+
+			if err := checkNotSuspended(&user); err != nil {
+				return err
+			}
+			delta, err := guard.Apply(user.MembershipType, user.Points, adjustment.Delta)
+			if err != nil {
+				return err
+			}
+			if err := checkPointsRate(tx, user.ID, delta, guard); err != nil {
+				return err
+			}
+			adjustment.Delta = delta
+			balance := user.Points + adjustment.Delta
+			if balance < 0 {
+				return domain.ErrNegativePoints
+			}
+			previousTier := user.MembershipType
+			user.Points = balance
+			user.MembershipType = tiers.TierAfter(previousTier, balance)
+
+			if err := tx.Save(&user).Error; err != nil {
+				return err
+			}
+			if err := recordTierChange(tx, user.ID, previousTier, user.MembershipType, adjustment.Reason); err != nil {
+				return err
+			}
+			txn := domain.PointsTransaction{UserID: user.ID, Delta: adjustment.Delta, Balance: balance, Reason: adjustment.Reason}
+			if err := tx.Create(&txn).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTierHistory retrieves a user's tier changes, oldest first
+func (r *userRepository) GetTierHistory(userID uint) ([]domain.TierChange, error) {
+	var changes []domain.TierChange
+	if err := r.db.Reader().Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SumPointsChange returns how many points a user's balance has moved, in either
+// direction, since the given time. It reads the primary so recent changes count.
+func (r *userRepository) SumPointsChange(userID uint, since time.Time) (int64, error) {
+	return sumPointsChange(r.db.DB, userID, since)
+}
+
+// sumPointsChange totals the absolute points moved by a user's transactions since the given time
+func sumPointsChange(tx *gorm.DB, userID uint, since time.Time) (int64, error) {
+	var total int64
+	err := tx.Model(&domain.PointsTransaction{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Select("COALESCE(SUM(ABS(delta)), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Delete soft-deletes a user by ID
 func (r *userRepository) Delete(id uint) error {
 	result := r.db.Delete(&domain.User{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("user not found")
+		return domain.ErrUserNotFound
 	}
 	return nil
 }
+
+// DeleteIfUnmodified deletes a user only while its UpdatedAt is one of revisions,
+// checked in the same statement so a concurrent update cannot slip in between
+func (r *userRepository) DeleteIfUnmodified(id uint, revisions []time.Time) error {
+	result := r.db.Where("updated_at IN ?", revisions).Delete(&domain.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := r.db.Model(&domain.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrUserNotFound
+	}
+	return domain.ErrUserModified
+}
+
+// Restore brings back a soft-deleted user, unless another user has registered
+// the same email in the meantime
+func (r *userRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		var taken int64
+		query, value := r.emailCondition(user.Email)
+		if err := tx.Model(&domain.User{}).Where(query, value).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return domain.ErrRestoreConflict
+		}
+		return tx.Unscoped().Model(&user).Update("deleted_at", nil).Error
+	})
+}
+
+// DeleteByIDs deletes all users with the given IDs and returns the IDs that were deleted
+func (r *userRepository) DeleteByIDs(ids []uint) ([]uint, error) {
+	var deleted []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id IN ?", ids).Pluck("id", &deleted).Error; err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		return tx.Delete(&domain.User{}, deleted).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// SumPoints returns the total points across users, optionally limited to one membership type
+func (r *userRepository) SumPoints(membershipType string) (int64, error) {
+	var total int64
+	query := r.db.Reader().Model(&domain.User{})
+	if membershipType != "" {
+		query = query.Where("membership_type = ?", membershipType)
+	}
+	if err := query.Select("COALESCE(SUM(points), 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetTierStats returns the user count and points totals per membership type,
+// optionally limited to one membership type
+func (r *userRepository) GetTierStats(membershipType string) ([]domain.TierStats, error) {
+	var stats []domain.TierStats
+	query := r.db.Reader().Model(&domain.User{})
+	if membershipType != "" {
+		query = query.Where("membership_type = ?", membershipType)
+	}
+	err := query.
+		Select("membership_type, COUNT(*) AS count, COALESCE(SUM(points), 0) AS total_points, COALESCE(AVG(points), 0) AS average_points").
+		Group("membership_type").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetUserStats returns the user count and points totals across all users,
+// optionally limited to one membership type, along with the per-tier figures
+func (r *userRepository) GetUserStats(membershipType string) (*domain.UserStats, error) {
+	var totals struct {
+		TotalUsers    int64
+		TotalPoints   int64
+		AveragePoints float64
+	}
+	query := r.db.Reader().Model(&domain.User{})
+	if membershipType != "" {
+		query = query.Where("membership_type = ?", membershipType)
+	}
+	err := query.
+		Select("COUNT(*) AS total_users, COALESCE(SUM(points), 0) AS total_points, COALESCE(AVG(points), 0) AS average_points").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := r.GetTierStats(membershipType)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserStats{
+		TotalUsers:    totals.TotalUsers,
+		TotalPoints:   totals.TotalPoints,
+		AveragePoints: totals.AveragePoints,
+		Tiers:         tiers,
+	}, nil
+}
+
+// CountTierMoves groups users by their stored tier and the tier their points
+// would give under the given thresholds
+func (r *userRepository) CountTierMoves(thresholds domain.TierThresholds) ([]domain.TierMove, error) {
+	var moves []domain.TierMove
+	err := r.db.Reader().Model(&domain.User{}).
+		Select(`membership_type AS "from", CASE WHEN points >= ? THEN 'Gold' WHEN points >= ? THEN 'Silver' ELSE 'Bronze' END AS "to", COUNT(*) AS count`,
+			thresholds.GoldMinPoints, thresholds.SilverMinPoints).
+		Group(`membership_type, "to"`).
+		Scan(&moves).Error
+	if err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// GetTopByPoints retrieves the users with the most points, optionally limited
+// to one membership type and to users with at least a minimum balance
+func (r *userRepository) GetTopByPoints(opts domain.LeaderboardOptions) ([]domain.User, error) {
+	var users []domain.User
+	query := r.db.Reader().Order("points DESC").Order("id ASC").Limit(opts.Limit)
+	if opts.MembershipType != "" {
+		query = query.Where("membership_type = ?", opts.MembershipType)
+	}
+	if opts.MinPoints != nil {
+		query = query.Where("points >= ?", *opts.MinPoints)
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountWithMorePoints counts the users whose balance is higher than points
+func (r *userRepository) CountWithMorePoints(points int) (int64, error) {
+	var count int64
+	if err := r.db.Reader().Model(&domain.User{}).Where("points > ?", points).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountJoinedSince counts the users who joined at or after the given time
+func (r *userRepository) CountJoinedSince(since time.Time) (int64, error) {
+	var count int64
+	if err := r.db.Reader().Model(&domain.User{}).Where("join_date >= ?", since).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -25,7 +28,7 @@ func (suite *UserRepositoryTestSuite) SetupTest() {
 	suite.db = &database.DB{DB: gormDB}
 
 	// Migrate the schema
-	err = suite.db.AutoMigrate(&domain.User{})
+	err = suite.db.AutoMigrate(&domain.User{}, &domain.PointsTransaction{}, &domain.TierChange{}, &domain.RevokedMembershipID{}, &domain.IdempotencyKey{})
 	suite.Require().NoError(err)
 
 	suite.repo = NewUserRepository(suite.db)
@@ -44,7 +47,7 @@ func (suite *UserRepositoryTestSuite) TestCreate() {
 	}
 
 	// Act
-	err := suite.repo.Create(user)
+	err := suite.repo.Create(user, 0)
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -64,7 +67,7 @@ func (suite *UserRepositoryTestSuite) TestGetByID() {
 		MembershipID:   "LBK123456",
 		Points:         100,
 	}
-	err := suite.repo.Create(user)
+	err := suite.repo.Create(user, 0)
 	suite.Require().NoError(err)
 
 	// Act
@@ -98,7 +101,7 @@ func (suite *UserRepositoryTestSuite) TestGetByEmail() {
 		MembershipID:   "LBK123456",
 		Points:         100,
 	}
-	err := suite.repo.Create(user)
+	err := suite.repo.Create(user, 0)
 	suite.Require().NoError(err)
 
 	// Act
@@ -140,7 +143,7 @@ func (suite *UserRepositoryTestSuite) TestGetAll() {
 	}
 
 	for _, user := range users {
-		err := suite.repo.Create(user)
+		err := suite.repo.Create(user, 0)
 		suite.Require().NoError(err)
 	}
 
@@ -163,13 +166,13 @@ func (suite *UserRepositoryTestSuite) TestUpdate() {
 		MembershipID:   "LBK123456",
 		Points:         100,
 	}
-	err := suite.repo.Create(user)
+	err := suite.repo.Create(user, 0)
 	suite.Require().NoError(err)
 
 	// Act
 	user.FirstName = "Jane"
 	user.Points = 200
-	err = suite.repo.Update(user)
+	err = suite.repo.Update(user, domain.PointsGuard{})
 
 	// Assert
 	assert.NoError(suite.T(), err)
@@ -181,6 +184,275 @@ func (suite *UserRepositoryTestSuite) TestUpdate() {
 	assert.Equal(suite.T(), 200, updated.Points)
 }
 
+func (suite *UserRepositoryTestSuite) TestUpdate_RecordsTierChange() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	user.FirstName = "Jane"
+	suite.Require().NoError(suite.repo.Update(user, domain.PointsGuard{}))
+	user.MembershipType = "Silver"
+	err := suite.repo.Update(user, domain.PointsGuard{})
+
+	// Assert
+	suite.NoError(err)
+
+	history, err := suite.repo.GetTierHistory(user.ID)
+	suite.NoError(err)
+	suite.Require().Len(history, 1)
+	suite.Equal("Bronze", history[0].From)
+	suite.Equal("Silver", history[0].To)
+	suite.Equal(domain.TierTriggerUpdate, history[0].Trigger)
+}
+
+func (suite *UserRepositoryTestSuite) TestSetMembershipType() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 200}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	err := suite.repo.SetMembershipType(user.ID, "Bronze", domain.TierTriggerReconcile)
+
+	// Assert
+	suite.NoError(err)
+
+	updated, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal("Bronze", updated.MembershipType)
+	suite.Equal(200, updated.Points)
+
+	history, err := suite.repo.GetTierHistory(user.ID)
+	suite.NoError(err)
+	suite.Require().Len(history, 1)
+	suite.Equal("Gold", history[0].From)
+	suite.Equal(domain.TierTriggerReconcile, history[0].Trigger)
+}
+
+func (suite *UserRepositoryTestSuite) TestAddPoints() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456", Points: 4800}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	err := suite.repo.AddPoints(user.ID, 500, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{})
+
+	// Assert
+	suite.NoError(err)
+
+	updated, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal(5300, updated.Points)
+	suite.Equal("Silver", updated.MembershipType)
+
+	var txn domain.PointsTransaction
+	suite.NoError(suite.db.Where("user_id = ?", user.ID).Last(&txn).Error)
+	suite.Equal(500, txn.Delta)
+	suite.Equal(5300, txn.Balance)
+	suite.Equal(domain.PointsReasonAdd, txn.Reason)
+}
+
+func (suite *UserRepositoryTestSuite) TestAddPoints_UpgradeOnly() {
+	tests := []struct {
+		name           string
+		allowDowngrade bool
+		expectTier     string
+	}{
+		{"keeps a higher tier", false, "Gold"},
+		{"downgrade allowed", true, "Bronze"},
+	}
+
+	for i, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange - a Gold user whose balance is below the Silver threshold
+			user := &domain.User{FirstName: "John", LastName: "Doe", Email: fmt.Sprintf("john%d@example.com", i), MembershipType: "Gold", MembershipID: fmt.Sprintf("LBK00000%d", i), Points: 200}
+			suite.Require().NoError(suite.repo.Create(user, 0))
+			tiers := domain.TierPolicy{Thresholds: domain.DefaultTierThresholds, AllowDowngrade: tt.allowDowngrade}
+
+			// Act
+			err := suite.repo.AddPoints(user.ID, 10, tiers, domain.PointsGuard{})
+
+			// Assert
+			suite.NoError(err)
+			updated, err := suite.repo.GetByID(user.ID)
+			suite.NoError(err)
+			suite.Equal(210, updated.Points)
+			suite.Equal(tt.expectTier, updated.MembershipType)
+		})
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestAdjustPoints_KeepsHigherTier() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 200}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	err := suite.repo.AdjustPoints([]domain.PointsAdjustment{{UserID: user.ID, Delta: 10, Reason: domain.PointsReasonImport}}, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{})
+
+	// Assert
+	suite.NoError(err)
+	updated, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal(210, updated.Points)
+	suite.Equal("Gold", updated.MembershipType)
+}
+
+func (suite *UserRepositoryTestSuite) TestPointsChange_KeepsOffLadderTier() {
+	tiers := domain.TierPolicy{Thresholds: domain.DefaultTierThresholds, AllowDowngrade: true}
+	tests := []struct {
+		name   string
+		change func(id uint) error
+	}{
+		{"add", func(id uint) error {
+			return suite.repo.AddPoints(id, 20000, tiers, domain.PointsGuard{})
+		}},
+		{"adjust", func(id uint) error {
+			return suite.repo.AdjustPoints([]domain.PointsAdjustment{{UserID: id, Delta: 20000, Reason: domain.PointsReasonImport}}, tiers, domain.PointsGuard{})
+		}},
+		{"set", func(id uint) error {
+			_, _, err := suite.repo.SetPoints(id, 20200, tiers, domain.PointsReasonSet, domain.PointsGuard{})
+			return err
+		}},
+	}
+
+	for i, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			user := &domain.User{FirstName: "John", LastName: "Doe", Email: fmt.Sprintf("john%d@example.com", i), MembershipType: "Platinum", MembershipID: fmt.Sprintf("LBK00000%d", i), Points: 200}
+			suite.Require().NoError(suite.repo.Create(user, 0))
+
+			// Act
+			err := tt.change(user.ID)
+
+			// Assert
+			suite.NoError(err)
+			updated, err := suite.repo.GetByID(user.ID)
+			suite.NoError(err)
+			suite.Equal(20200, updated.Points)
+			suite.Equal("Platinum", updated.MembershipType)
+		})
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestAddPoints_Negative() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	err := suite.repo.AddPoints(user.ID, -101, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{})
+	notFoundErr := suite.repo.AddPoints(999, 10, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{})
+
+	// Assert
+	suite.ErrorIs(err, domain.ErrNegativePoints)
+	suite.EqualError(notFoundErr, "user not found")
+
+	unchanged, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal(100, unchanged.Points)
+}
+
+func (suite *UserRepositoryTestSuite) TestAddPoints_Floor() {
+	tests := []struct {
+		name         string
+		clamp        bool
+		delta        int
+		expectPoints int
+		expectErr    error
+	}{
+		{"reject below floor", false, -12000, 15000, domain.ErrBelowPointsFloor},
+		{"clamp to floor", true, -12000, 10000, nil},
+		{"deduction above floor", false, -4000, 11000, nil},
+	}
+
+	for i, tt := range tests {
+		suite.Run(tt.name, func() {
+			// Arrange
+			user := &domain.User{FirstName: "John", LastName: "Doe", Email: fmt.Sprintf("john%d@example.com", i), MembershipType: "Gold", MembershipID: fmt.Sprintf("LBK00000%d", i), Points: 15000}
+			suite.Require().NoError(suite.repo.Create(user, 0))
+			guard := domain.PointsGuard{Floors: map[string]int{"Gold": 10000}, ClampToFloor: tt.clamp}
+
+			// Act
+			err := suite.repo.AddPoints(user.ID, tt.delta, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, guard)
+
+			// Assert
+			suite.ErrorIs(err, tt.expectErr)
+			updated, err := suite.repo.GetByID(user.ID)
+			suite.NoError(err)
+			suite.Equal(tt.expectPoints, updated.Points)
+		})
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestAddPoints_RateLimit() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+	guard := domain.PointsGuard{RateLimit: 1000, RateWindow: time.Hour}
+
+	// Act
+	within := suite.repo.AddPoints(user.ID, 800, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, guard)
+	over := suite.repo.AddPoints(user.ID, -300, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, guard)
+	updateErr := suite.repo.Update(&domain.User{ID: user.ID, FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 1100}, guard)
+
+	// Assert
+	suite.NoError(within)
+	suite.ErrorIs(over, domain.ErrPointsRateExceeded)
+	suite.ErrorIs(updateErr, domain.ErrPointsRateExceeded)
+
+	unchanged, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal(800, unchanged.Points)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdate_KeepsConcurrentChanges() {
+	// Arrange - a copy read before the user was suspended and given a new membership ID
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+	stale, err := suite.repo.GetByID(user.ID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.UpdateStatus(user.ID, domain.UserStatusSuspended))
+	suite.Require().NoError(suite.repo.UpdateMembershipID(user.ID, "LBK654321"))
+
+	// Act
+	stale.FirstName = "Jane"
+	err = suite.repo.Update(stale, domain.PointsGuard{})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	updated, err := suite.repo.GetByID(user.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Jane", updated.FirstName)
+	assert.Equal(suite.T(), domain.UserStatusSuspended, updated.Status)
+	assert.Equal(suite.T(), "LBK654321", updated.MembershipID)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdate_UserNotFound() {
+	// Act
+	err := suite.repo.Update(&domain.User{ID: 999, FirstName: "John", LastName: "Doe", Email: "john@example.com"}, domain.PointsGuard{})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, domain.ErrUserNotFound)
+}
+
+func (suite *UserRepositoryTestSuite) TestUpdate_PointsFloor() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+	guard := domain.PointsGuard{Floors: map[string]int{"Gold": 10000}}
+
+	// Act
+	user.Points = 9000
+	err := suite.repo.Update(user, guard)
+
+	// Assert
+	suite.ErrorIs(err, domain.ErrBelowPointsFloor)
+	unchanged, err := suite.repo.GetByID(user.ID)
+	suite.NoError(err)
+	suite.Equal(15000, unchanged.Points)
+}
+
 func (suite *UserRepositoryTestSuite) TestDelete() {
 	// Arrange
 	user := &domain.User{
@@ -190,7 +462,7 @@ func (suite *UserRepositoryTestSuite) TestDelete() {
 		MembershipType: "Gold",
 		MembershipID:   "LBK123456",
 	}
-	err := suite.repo.Create(user)
+	err := suite.repo.Create(user, 0)
 	suite.Require().NoError(err)
 
 	// Act
@@ -204,6 +476,23 @@ func (suite *UserRepositoryTestSuite) TestDelete() {
 	assert.Error(suite.T(), err)
 }
 
+func (suite *UserRepositoryTestSuite) TestEmailInUse_DeletedUser() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+	suite.Require().NoError(suite.repo.Delete(user.ID))
+
+	// Act
+	counted, countedErr := suite.repo.EmailInUse("john@example.com", true)
+	ignored, ignoredErr := suite.repo.EmailInUse("john@example.com", false)
+
+	// Assert
+	assert.NoError(suite.T(), countedErr)
+	assert.True(suite.T(), counted)
+	assert.NoError(suite.T(), ignoredErr)
+	assert.False(suite.T(), ignored)
+}
+
 func (suite *UserRepositoryTestSuite) TestDelete_NotFound() {
 	// Act
 	err := suite.repo.Delete(999)
@@ -213,6 +502,353 @@ func (suite *UserRepositoryTestSuite) TestDelete_NotFound() {
 	assert.Equal(suite.T(), "user not found", err.Error())
 }
 
+func (suite *UserRepositoryTestSuite) TestDeleteByIDs() {
+	// Arrange
+	user := &domain.User{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Email:        "john@example.com",
+		MembershipID: "LBK123456",
+	}
+	err := suite.repo.Create(user, 0)
+	suite.Require().NoError(err)
+
+	// Act
+	deleted, err := suite.repo.DeleteByIDs([]uint{user.ID, 999})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []uint{user.ID}, deleted)
+
+	_, err = suite.repo.GetByID(user.ID)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *UserRepositoryTestSuite) TestReadsUseReplica() {
+	// Arrange - a replica holding a user the primary does not have
+	replica, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(replica.AutoMigrate(&domain.User{}))
+
+	replicaUser := &domain.User{
+		FirstName:    "Replica",
+		LastName:     "User",
+		Email:        "replica@example.com",
+		MembershipID: "LBK000001",
+	}
+	suite.Require().NoError(replica.Create(replicaUser).Error)
+
+	suite.db.Replica = replica
+
+	// Act
+	users, err := suite.repo.GetAll()
+	suite.Require().NoError(err)
+	user, err := suite.repo.GetByID(replicaUser.ID)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), "Replica", user.FirstName)
+
+	// Writes still go to the primary
+	primaryUser := &domain.User{FirstName: "Primary", LastName: "User", Email: "primary@example.com", MembershipID: "LBK000002"}
+	suite.Require().NoError(suite.repo.Create(primaryUser, 0))
+	var count int64
+	suite.db.DB.Model(&domain.User{}).Count(&count)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetByIDFromPrimary_IgnoresReplica() {
+	// Arrange - a replica lagging behind a write to the primary
+	user := &domain.User{FirstName: "Fresh", LastName: "User", Email: "fresh@example.com", MembershipID: "LBK000001"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	replica, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(replica.AutoMigrate(&domain.User{}))
+	suite.db.Replica = replica
+
+	// Act
+	_, replicaErr := suite.repo.GetByID(user.ID)
+	found, err := suite.repo.GetByIDFromPrimary(user.ID)
+
+	// Assert
+	assert.ErrorIs(suite.T(), replicaErr, domain.ErrUserNotFound)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Fresh", found.FirstName)
+}
+
+func (suite *UserRepositoryTestSuite) TestSumPoints_EmptyTable() {
+	// Act
+	total, err := suite.repo.SumPoints("")
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), total)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserStats() {
+	// Arrange
+	users := []*domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001", MembershipType: "Gold", Points: 12000},
+		{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", MembershipID: "LBK000002", MembershipType: "Bronze", Points: 100},
+		{FirstName: "Joe", LastName: "Doe", Email: "joe@example.com", MembershipID: "LBK000003", MembershipType: "Bronze", Points: 500},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.repo.Create(user, 0))
+	}
+
+	// Act
+	stats, err := suite.repo.GetUserStats("")
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), stats.TotalUsers)
+	assert.Equal(suite.T(), int64(12600), stats.TotalPoints)
+	assert.Equal(suite.T(), 4200.0, stats.AveragePoints)
+	assert.ElementsMatch(suite.T(), []domain.TierStats{
+		{MembershipType: "Bronze", Count: 2, TotalPoints: 600, AveragePoints: 300},
+		{MembershipType: "Gold", Count: 1, TotalPoints: 12000, AveragePoints: 12000},
+	}, stats.Tiers)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetUserStats_EmptyTable() {
+	// Act
+	stats, err := suite.repo.GetUserStats("")
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &domain.UserStats{}, stats)
+}
+
+func (suite *UserRepositoryTestSuite) TestGetByMembershipID() {
+	// Arrange
+	user := &domain.User{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Email:        "john@example.com",
+		MembershipID: "LBK123456",
+	}
+	err := suite.repo.Create(user, 0)
+	suite.Require().NoError(err)
+
+	// Act
+	found, err := suite.repo.GetByMembershipID("LBK123456")
+	_, missingErr := suite.repo.GetByMembershipID("LBK999999")
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.ID, found.ID)
+	assert.Equal(suite.T(), "user not found", missingErr.Error())
+}
+
+func (suite *UserRepositoryTestSuite) TestMembershipIDInUse() {
+	// Arrange - one active user, one soft-deleted user and one revoked card
+	active := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001"}
+	deleted := &domain.User{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", MembershipID: "LBK000002"}
+	suite.Require().NoError(suite.repo.Create(active, 0))
+	suite.Require().NoError(suite.repo.Create(deleted, 0))
+	suite.Require().NoError(suite.repo.Delete(deleted.ID))
+	_, err := suite.repo.ReplaceMembershipID(active.ID, "LBK000003")
+	suite.Require().NoError(err)
+
+	for id, expected := range map[string]bool{
+		"LBK000001": true, // revoked by the reissue
+		"LBK000002": true, // held by a deleted user
+		"LBK000003": true, // held by an active user
+		"LBK000004": false,
+	} {
+		// Act
+		inUse, err := suite.repo.MembershipIDInUse(id)
+
+		// Assert
+		suite.NoError(err)
+		suite.Equal(expected, inUse, id)
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestList() {
+	// Arrange
+	for i := 1; i <= 3; i++ {
+		user := &domain.User{
+			FirstName:    "User",
+			LastName:     fmt.Sprintf("%d", i),
+			Email:        fmt.Sprintf("user%d@example.com", i),
+			MembershipID: fmt.Sprintf("LBK00000%d", i),
+		}
+		suite.Require().NoError(suite.repo.Create(user, 0))
+	}
+
+	// Act
+	users, total, err := suite.repo.List(domain.UserListOptions{Page: 2, Limit: 2})
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), total)
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), "3", users[0].LastName)
+}
+
+func (suite *UserRepositoryTestSuite) TestPointsLedger_MatchesBalance() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+	user.Points = 6000
+	suite.Require().NoError(suite.repo.Update(user, domain.PointsGuard{}))
+	user.FirstName = "Johnny"
+	suite.Require().NoError(suite.repo.Update(user, domain.PointsGuard{}))
+	suite.Require().NoError(suite.repo.AddPoints(user.ID, -50, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{}))
+	_, _, err := suite.repo.SetPoints(user.ID, 20, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds, AllowDowngrade: true}, domain.PointsReasonSet, domain.PointsGuard{})
+	suite.Require().NoError(err)
+
+	// Act
+	txns, err := NewPointsTransactionRepository(suite.db).ListByUser(user.ID)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(txns, 4)
+	assert.Equal(suite.T(), []int{100, 5900, -50, -5930}, []int{txns[0].Delta, txns[1].Delta, txns[2].Delta, txns[3].Delta})
+	total := 0
+	for _, txn := range txns {
+		total += txn.Delta
+		assert.Equal(suite.T(), total, txn.Balance)
+	}
+	stored, err := suite.repo.GetByID(user.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), stored.Points, total)
+}
+
+func (suite *UserRepositoryTestSuite) TestCreate_ZeroPointsOpensNoLedger() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+
+	// Act
+	err := suite.repo.Create(user, 0)
+
+	// Assert
+	suite.Require().NoError(err)
+	txns, err := NewPointsTransactionRepository(suite.db).ListByUser(user.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), txns)
+}
+
+func (suite *UserRepositoryTestSuite) TestCreate_RecordsWelcomeBonusSeparately() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 600}
+
+	// Act
+	err := suite.repo.Create(user, 500)
+
+	// Assert
+	suite.Require().NoError(err)
+	txns, err := NewPointsTransactionRepository(suite.db).ListByUser(user.ID)
+	suite.Require().NoError(err)
+	suite.Require().Len(txns, 2)
+	assert.Equal(suite.T(), domain.PointsReasonInitial, txns[0].Reason)
+	assert.Equal(suite.T(), 100, txns[0].Delta)
+	assert.Equal(suite.T(), 100, txns[0].Balance)
+	assert.Equal(suite.T(), domain.PointsReasonWelcomeBonus, txns[1].Reason)
+	assert.Equal(suite.T(), 500, txns[1].Delta)
+	assert.Equal(suite.T(), 600, txns[1].Balance)
+}
+
+func (suite *UserRepositoryTestSuite) TestWithTransaction_Commits() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+
+	// Act
+	err := suite.repo.WithTransaction(func(txRepo domain.UserRepository) error {
+		if err := txRepo.Create(user, 0); err != nil {
+			return err
+		}
+		_, _, err := txRepo.SetPoints(user.ID, 500, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsReasonSet, domain.PointsGuard{})
+		return err
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	stored, err := suite.repo.GetByID(user.ID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 500, stored.Points)
+	txns, err := NewPointsTransactionRepository(suite.db).ListByUser(user.ID)
+	suite.Require().NoError(err)
+	assert.Len(suite.T(), txns, 2)
+}
+
+func (suite *UserRepositoryTestSuite) TestWithTransaction_RollsBack() {
+	// Arrange
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+	failure := errors.New("boom")
+
+	// Act
+	err := suite.repo.WithTransaction(func(txRepo domain.UserRepository) error {
+		if err := txRepo.Create(user, 0); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, failure)
+	_, err = suite.repo.GetByEmail("john@example.com")
+	assert.Error(suite.T(), err)
+	txns, err := NewPointsTransactionRepository(suite.db).ListByUser(user.ID)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), txns)
+}
+
+func (suite *UserRepositoryTestSuite) TestPIIEncryption() {
+	// Arrange
+	cipher, err := database.NewPIICipher("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	suite.Require().NoError(err)
+	suite.db.EnablePIIEncryption(cipher)
+	user := &domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.repo.Create(user, 0))
+
+	// Act
+	byEmail, err := suite.repo.GetByEmail("john@example.com")
+	suite.Require().NoError(err)
+	existing, err := suite.repo.FindExistingEmails([]string{"john@example.com", "jane@example.com"}, false)
+	suite.Require().NoError(err)
+	byName, _, err := suite.repo.Search("joh", domain.UserListOptions{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+	byPrefix, _, err := suite.repo.Search("enc", domain.UserListOptions{Page: 1, Limit: 10})
+	suite.Require().NoError(err)
+
+	// Assert
+	var raw struct {
+		Email string
+		Phone string
+	}
+	suite.Require().NoError(suite.db.Table("users").Where("id = ?", user.ID).Scan(&raw).Error)
+	assert.NotContains(suite.T(), raw.Email, "john")
+	assert.NotContains(suite.T(), raw.Phone, "081")
+
+	assert.Equal(suite.T(), user.ID, byEmail.ID)
+	assert.Equal(suite.T(), "john@example.com", byEmail.Email)
+	assert.Equal(suite.T(), "081-234-5678", byEmail.Phone)
+	assert.Equal(suite.T(), []string{"john@example.com"}, existing)
+	assert.Len(suite.T(), byName, 1)
+	assert.Empty(suite.T(), byPrefix)
+}
+
+func (suite *UserRepositoryTestSuite) TestPIIEncryption_UniqueEmail() {
+	// Arrange
+	cipher, err := database.NewPIICipher("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	suite.Require().NoError(err)
+	suite.db.EnablePIIEncryption(cipher)
+	suite.Require().NoError(suite.repo.Create(&domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}, 0))
+
+	// Act
+	err = suite.repo.Create(&domain.User{FirstName: "Johnny", LastName: "Doe", Email: "John@Example.com", MembershipID: "LBK123457"}, 0)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	var count int64
+	suite.Require().NoError(suite.db.Model(&domain.User{}).Count(&count).Error)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// ComputeFields calculates the requested computed fields for a user. Fields are
+// only computed on request since some, like rank, need extra queries.
+func (u *userUseCase) ComputeFields(user *domain.User, fields []string) (map[string]int, error) {
+	for _, field := range fields {
+		if !slices.Contains(domain.ComputedFields, field) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownComputedField, field)
+		}
+	}
+
+	computed := make(map[string]int, len(fields))
+	for _, field := range fields {
+		switch field {
+		case domain.ComputedMembershipDays:
+			computed[field] = int(time.Since(user.JoinDate).Hours() / 24)
+		case domain.ComputedPointsToNextTier:
+			computed[field] = u.tierThresholds().PointsToNextTier(user.Points)
+		case domain.ComputedProfileCompleteness:
+			computed[field] = profileCompleteness(user)
+		case domain.ComputedRank:
+			above, err := u.userRepo.CountWithMorePoints(user.Points)
+			if err != nil {
+				return nil, err
+			}
+			computed[field] = int(above) + 1
+		}
+	}
+	return computed, nil
+}
+
+// profileCompleteness returns the percentage of a user's profile fields that are filled in
+func profileCompleteness(user *domain.User) int {
+	fields := []string{user.FirstName, user.LastName, user.Email, user.Phone}
+	filled := 0
+	for _, field := range fields {
+		if field != "" {
+			filled++
+		}
+	}
+	return filled * 100 / len(fields)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// dashboardTopUsers is how many leaders the dashboard shows
+const dashboardTopUsers = 5
+
+// maxDashboardQueries caps how many dashboard sections are queried at once
+const maxDashboardQueries = 2
+
+// GetDashboard gathers the dashboard metrics, querying the sections concurrently
+func (u *userUseCase) GetDashboard() (*domain.Dashboard, error) {
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	dashboard := &domain.Dashboard{}
+	var stats []domain.TierStats
+	err := runBounded(maxDashboardQueries,
+		func() (err error) {
+			stats, err = u.GetTierStats("")
+			return err
+		},
+		func() (err error) {
+			dashboard.TotalPoints, err = u.GetTotalPoints("")
+			return err
+		},
+		func() (err error) {
+			dashboard.NewThisMonth, err = u.userRepo.CountJoinedSince(monthStart)
+			return err
+		},
+		func() (err error) {
+			dashboard.TopUsers, err = u.GetLeaderboard(domain.LeaderboardOptions{Limit: dashboardTopUsers})
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	types := u.membershipTypes()
+	dashboard.TierCounts = make(map[string]int64, len(types))
+	for _, membershipType := range types {
+		dashboard.TierCounts[membershipType] = 0
+	}
+	for _, tier := range stats {
+		dashboard.TierCounts[tier.MembershipType] = tier.Count
+		dashboard.TotalUsers += tier.Count
+	}
+	return dashboard, nil
+}
+
+// runBounded runs tasks concurrently, at most limit at a time, and returns their
+// errors joined once all have finished
+func runBounded(limit int, tasks ...func() error) error {
+	sem := make(chan struct{}, limit)
+	errs := make([]error, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = task()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
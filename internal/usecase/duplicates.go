@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// defaultDuplicateMinScore is used when no minimum score is configured
+const defaultDuplicateMinScore = 80
+
+// defaultDuplicateRules are used when no duplicate rules are configured
+var defaultDuplicateRules = map[string]int{
+	"phone":      50,
+	"last_name":  30,
+	"first_name": 20,
+}
+
+// duplicateMatchers report, per rule name, whether two users match on that signal.
+// Add an entry here to make a new rule usable in the duplicate rules setting.
+var duplicateMatchers = map[string]func(a, b *domain.User) bool{
+	"phone": func(a, b *domain.User) bool {
+		phone := normalizePhone(a.Phone)
+		return phone != "" && phone == normalizePhone(b.Phone)
+	},
+	"last_name": func(a, b *domain.User) bool {
+		return sameName(a.LastName, b.LastName)
+	},
+	"first_name": func(a, b *domain.User) bool {
+		return sameName(a.FirstName, b.FirstName)
+	},
+}
+
+// FindDuplicateCandidates compares every pair of users against the configured
+// rules and returns the pairs scoring at least the configured minimum, highest first
+func (u *userUseCase) FindDuplicateCandidates() ([]domain.DuplicateCandidate, error) {
+	users, err := u.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := u.config.DuplicateRules
+	if len(rules) == 0 {
+		rules = defaultDuplicateRules
+	}
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		if _, ok := duplicateMatchers[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	minScore := u.config.DuplicateMinScore
+	if minScore <= 0 {
+		minScore = defaultDuplicateMinScore
+	}
+
+	candidates := []domain.DuplicateCandidate{}
+	for i := range users {
+		for j := i + 1; j < len(users); j++ {
+			candidate := domain.DuplicateCandidate{UserID: users[i].ID, OtherUserID: users[j].ID}
+			for _, name := range names {
+				if duplicateMatchers[name](&users[i], &users[j]) {
+					candidate.Score += rules[name]
+					candidate.Matched = append(candidate.Matched, name)
+				}
+			}
+			if candidate.Score >= minScore {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// normalizePhone keeps only the digits of a phone number
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// sameName compares two names ignoring case and surrounding whitespace
+func sameName(a, b string) bool {
+	a = strings.TrimSpace(a)
+	return a != "" && strings.EqualFold(a, strings.TrimSpace(b))
+}
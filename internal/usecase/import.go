@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// Import defaults used when the limits are not configured
+const (
+	defaultImportMaxBytes = 10 << 20
+	defaultImportTimeout  = 30 * time.Second
+)
+
+// importUsers creates one user per CSV row and reports the outcome of each row.
+// The first row must be a header naming the columns in domain.UserCSVColumns.
+func (u *userUseCase) importUsers(r io.Reader) ([]domain.ImportRowResult, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidImport)
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range domain.UserCSVRequiredColumns {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidImport, required)
+		}
+	}
+
+	results := []domain.ImportRowResult{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// A stream error (size limit, timeout) stops the import; earlier rows stand
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return results, err
+			}
+			results = append(results, domain.ImportRowResult{Row: row, Status: domain.ImportRowError, Error: err.Error()})
+			continue
+		}
+
+		results = append(results, u.importRow(row, record, columns))
+	}
+
+	return results, nil
+}
+
+// importRow creates the user described by a single CSV record
+func (u *userUseCase) importRow(row int, record []string, columns map[string]int) domain.ImportRowResult {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	req := domain.CreateUserRequest{
+		FirstName:      field(domain.UserCSVFirstName),
+		LastName:       field(domain.UserCSVLastName),
+		Email:          field(domain.UserCSVEmail),
+		Phone:          field(domain.UserCSVPhone),
+		MembershipType: field(domain.UserCSVMembershipType),
+	}
+	if points := field(domain.UserCSVPoints); points != "" {
+		value, err := strconv.Atoi(points)
+		if err != nil {
+			return domain.ImportRowResult{Row: row, Status: domain.ImportRowError, Error: "invalid points value"}
+		}
+		req.Points = value
+	}
+
+	user, err := u.CreateUser(req)
+	if err != nil {
+		return domain.ImportRowResult{Row: row, Status: domain.ImportRowError, Error: err.Error()}
+	}
+	return domain.ImportRowResult{Row: row, Status: domain.ImportRowCreated, UserID: user.ID}
+}
+
+// ImportUsersFromURL streams a remote CSV into importUsers. Only http(s) URLs on
+// the configured host allowlist are fetched, including across redirects.
+func (u *userUseCase) ImportUsersFromURL(ctx context.Context, rawURL string) ([]domain.ImportRowResult, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || !u.importHostAllowed(target) {
+		return nil, domain.ErrImportURLNotAllowed
+	}
+
+	timeout := u.config.ImportURLTimeout
+	if timeout <= 0 {
+		timeout = defaultImportTimeout
+	}
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !u.importHostAllowed(req.URL) {
+				return domain.ErrImportURLNotAllowed
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, domain.ErrImportURLNotAllowed
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportURLNotAllowed) {
+			return nil, domain.ErrImportURLNotAllowed
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrImportFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %d", domain.ErrImportFetchFailed, resp.StatusCode)
+	}
+
+	maxBytes := u.config.ImportMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultImportMaxBytes
+	}
+	return u.importUsers(&limitedReader{r: resp.Body, remaining: maxBytes})
+}
+
+// importHostAllowed reports whether a URL may be fetched for import
+func (u *userUseCase) importHostAllowed(target *url.URL) bool {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(target.Hostname())
+	for _, allowed := range u.config.ImportAllowedHosts {
+		if host == strings.ToLower(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedReader fails with ErrImportTooLarge instead of silently truncating
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for more data so an exact-size body is still accepted
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, domain.ErrImportTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// normalizeName applies the configured case rule to a name. Only Latin letters
+// are transformed so Thai and other scripts are stored exactly as given.
+func normalizeName(name, nameCase string) string {
+	switch nameCase {
+	case config.NameCaseTitle:
+		runes := []rune(name)
+		for i, r := range runes {
+			if !unicode.Is(unicode.Latin, r) {
+				continue
+			}
+			if i == 0 || !unicode.IsLetter(runes[i-1]) {
+				runes[i] = unicode.ToUpper(r)
+			} else {
+				runes[i] = unicode.ToLower(r)
+			}
+		}
+		return string(runes)
+	case config.NameCaseUpper:
+		runes := []rune(name)
+		for i, r := range runes {
+			if unicode.Is(unicode.Latin, r) {
+				runes[i] = unicode.ToUpper(r)
+			}
+		}
+		return string(runes)
+	default:
+		return name
+	}
+}
+
+// normalizeEmail returns the canonical form of an email used for comparisons
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeMembershipID returns the canonical form of a membership ID, which is
+// issued in upper case
+func normalizeMembershipID(membershipID string) string {
+	return strings.ToUpper(strings.TrimSpace(membershipID))
+}
+
+// NormalizeContacts returns the canonical form of each email and phone number
+// with whether it passes the rules applied to stored users. Nothing is read from
+// or written to the database.
+func (u *userUseCase) NormalizeContacts(req domain.NormalizeRequest) (*domain.NormalizeResult, error) {
+	total := len(req.Emails) + len(req.Phones)
+	if total == 0 {
+		return nil, fmt.Errorf("%w: at least one email or phone is required", domain.ErrInvalidBatch)
+	}
+	if total > maxNormalizeBatch {
+		return nil, fmt.Errorf("%w: at most %d values can be normalized at once", domain.ErrInvalidBatch, maxNormalizeBatch)
+	}
+
+	result := &domain.NormalizeResult{
+		Emails: make([]domain.NormalizedValue, 0, len(req.Emails)),
+		Phones: make([]domain.NormalizedValue, 0, len(req.Phones)),
+	}
+	for _, email := range req.Emails {
+		value := domain.NormalizedValue{Input: email, Normalized: normalizeEmail(email), Valid: true}
+		if !validEmail(value.Normalized) {
+			value.Valid, value.Error = false, "must be a valid email address"
+		}
+		result.Emails = append(result.Emails, value)
+	}
+	for _, phone := range req.Phones {
+		value := domain.NormalizedValue{Input: phone, Normalized: normalizePhone(phone), Valid: true}
+		if message := checkPhone(phone); message != "" {
+			value.Valid, value.Error = false, message
+		}
+		result.Phones = append(result.Phones, value)
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/internal/mocks"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		nameCase string
+		expected string
+	}{
+		{"preserve keeps input", "john doe", config.NameCasePreserve, "john doe"},
+		{"title cases latin words", "john doe", config.NameCaseTitle, "John Doe"},
+		{"title lowercases the rest", "mARY-ANNE", config.NameCaseTitle, "Mary-Anne"},
+		{"upper cases latin letters", "john doe", config.NameCaseUpper, "JOHN DOE"},
+		{"title leaves thai untouched", "สมชาย", config.NameCaseTitle, "สมชาย"},
+		{"upper leaves thai untouched", "สมชาย ใจดี", config.NameCaseUpper, "สมชาย ใจดี"},
+		{"mixed scripts only touch latin", "สมชาย smith", config.NameCaseTitle, "สมชาย Smith"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeName(tt.input, tt.nameCase))
+		})
+	}
+}
+
+func TestUserUseCase_NormalizeContacts(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	req := domain.NormalizeRequest{
+		Emails: []string{" John@Example.com ", "jane@example.com", "not-an-email"},
+		Phones: []string{"081-234-5678", "0812345678", "+66 (81) 234 5678", "081-CALL-NOW", "1234"},
+	}
+
+	// Act
+	result, err := useCase.NormalizeContacts(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.NormalizedValue{
+		{Input: " John@Example.com ", Normalized: "john@example.com", Valid: true},
+		{Input: "jane@example.com", Normalized: "jane@example.com", Valid: true},
+		{Input: "not-an-email", Normalized: "not-an-email", Error: "must be a valid email address"},
+	}, result.Emails)
+	assert.Equal(t, []domain.NormalizedValue{
+		{Input: "081-234-5678", Normalized: "0812345678", Valid: true},
+		{Input: "0812345678", Normalized: "0812345678", Valid: true},
+		{Input: "+66 (81) 234 5678", Normalized: "66812345678", Valid: true},
+		{Input: "081-CALL-NOW", Normalized: "081", Error: "must contain only digits and separators"},
+		{Input: "1234", Normalized: "1234", Error: "must have 9 to 15 digits"},
+	}, result.Phones)
+	mockRepo.AssertNotCalled(t, "FindExistingEmails", mock.Anything)
+}
+
+func TestUserUseCase_NormalizeContacts_InvalidBatch(t *testing.T) {
+	// Arrange
+	useCase := NewUserUseCase(new(mocks.MockUserRepository), &config.Config{})
+
+	// Act
+	_, emptyErr := useCase.NormalizeContacts(domain.NormalizeRequest{})
+	_, tooManyErr := useCase.NormalizeContacts(domain.NormalizeRequest{Phones: make([]string, maxNormalizeBatch+1)})
+
+	// Assert
+	assert.ErrorIs(t, emptyErr, domain.ErrInvalidBatch)
+	assert.ErrorIs(t, tooManyErr, domain.ErrInvalidBatch)
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// pendingAdjustment is a validated import row waiting to be applied
+type pendingAdjustment struct {
+	result     int
+	adjustment domain.PointsAdjustment
+}
+
+// ImportPoints applies a CSV of membership_id,delta,reason rows as points
+// transactions and reports the outcome of each row. Rows that would take a
+// balance below zero or move a member's balance past the rate limit are rejected,
+// and deductions below a tier's floor are rejected or clamped per the configured
+// policy. In atomic mode any invalid row rejects the
+// whole file with ErrImportRejected; otherwise the valid rows are applied.
+func (u *userUseCase) ImportPoints(r io.Reader, mode string) ([]domain.ImportRowResult, error) {
+	if mode == "" {
+		mode = domain.ImportModePartial
+	}
+	if mode != domain.ImportModeAtomic && mode != domain.ImportModePartial {
+		return nil, fmt.Errorf("%w: %q, use atomic or partial", domain.ErrInvalidImportMode, mode)
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidImport)
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"membership_id", "delta"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidImport, required)
+		}
+	}
+
+	results := []domain.ImportRowResult{}
+	pending := []pendingAdjustment{}
+	// balances tracks each user's balance as earlier rows are applied so
+	// several rows for the same member are checked together
+	balances := make(map[uint]int)
+	// moved tracks the points each user's balance has moved within the rate
+	// window, earlier rows included
+	moved := make(map[uint]int64)
+	// tiers tracks the tier earlier rows leave each user in
+	tiers := make(map[uint]string)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return results, err
+			}
+			results = append(results, domain.ImportRowResult{Row: row, Status: domain.ImportRowError, Error: err.Error()})
+			continue
+		}
+
+		adjustment, err := u.parsePointsRow(record, columns, balances, moved, tiers)
+		if err != nil {
+			results = append(results, domain.ImportRowResult{Row: row, Status: domain.ImportRowError, UserID: adjustment.UserID, Error: err.Error()})
+			continue
+		}
+		balances[adjustment.UserID] += adjustment.Delta
+		moved[adjustment.UserID] += int64(max(adjustment.Delta, -adjustment.Delta))
+		pending = append(pending, pendingAdjustment{result: len(results), adjustment: adjustment})
+		results = append(results, domain.ImportRowResult{Row: row, Status: domain.ImportRowApplied, UserID: adjustment.UserID})
+	}
+
+	if mode == domain.ImportModeAtomic {
+		return u.applyAtomic(results, pending)
+	}
+
+	for _, p := range pending {
+		if err := u.userRepo.AdjustPoints([]domain.PointsAdjustment{p.adjustment}, u.tierPolicy(false), u.pointsGuard()); err != nil {
+			results[p.result].Status = domain.ImportRowError
+			results[p.result].Error = err.Error()
+			continue
+		}
+		u.publish(domain.EventUserUpdated, p.adjustment.UserID)
+	}
+	return results, nil
+}
+
+// applyAtomic applies every pending adjustment in one transaction, or none of
+// them when any row failed validation or the transaction fails
+func (u *userUseCase) applyAtomic(results []domain.ImportRowResult, pending []pendingAdjustment) ([]domain.ImportRowResult, error) {
+	skipAll := func() {
+		for _, p := range pending {
+			results[p.result].Status = domain.ImportRowSkipped
+		}
+	}
+
+	if len(pending) != len(results) {
+		skipAll()
+		return results, domain.ErrImportRejected
+	}
+
+	adjustments := make([]domain.PointsAdjustment, len(pending))
+	for i, p := range pending {
+		adjustments[i] = p.adjustment
+	}
+	if err := u.userRepo.AdjustPoints(adjustments, u.tierPolicy(false), u.pointsGuard()); err != nil {
+		skipAll()
+		return results, fmt.Errorf("%w: %v", domain.ErrImportRejected, err)
+	}
+	for _, adjustment := range adjustments {
+		u.publish(domain.EventUserUpdated, adjustment.UserID)
+	}
+	return results, nil
+}
+
+// parsePointsRow resolves the member and delta of a single CSV record, checking
+// the delta against the member's balance and points moved after earlier rows
+func (u *userUseCase) parsePointsRow(record []string, columns map[string]int, balances map[uint]int, moved map[uint]int64, tiers map[uint]string) (domain.PointsAdjustment, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	delta, err := strconv.Atoi(field("delta"))
+	if err != nil {
+		return domain.PointsAdjustment{}, errors.New("invalid delta value")
+	}
+	user, err := u.userRepo.GetByMembershipID(field("membership_id"))
+	if err != nil || user == nil {
+		return domain.PointsAdjustment{}, domain.ErrUserNotFound
+	}
+	if user.Status == domain.UserStatusSuspended {
+		return domain.PointsAdjustment{UserID: user.ID}, domain.ErrUserSuspended
+	}
+
+	adjustment := domain.PointsAdjustment{UserID: user.ID, Delta: delta, Reason: field("reason")}
+	if adjustment.Reason == "" {
+		adjustment.Reason = domain.PointsReasonImport
+	}
+
+	// Earlier rows for the member may have moved them up a tier
+	balance, tier := user.Points+balances[user.ID], user.MembershipType
+	if earlier, seen := tiers[user.ID]; seen {
+		tier = earlier
+	}
+	if adjustment.Delta, err = u.pointsGuard().Apply(tier, balance, delta); err != nil {
+		return adjustment, err
+	}
+	if balance+adjustment.Delta < 0 {
+		return adjustment, domain.ErrNegativePoints
+	}
+
+	guard := u.pointsGuard()
+	if guard.RateLimit > 0 {
+		if _, seen := moved[user.ID]; !seen {
+			if moved[user.ID], err = u.userRepo.SumPointsChange(user.ID, time.Now().Add(-guard.RateWindow)); err != nil {
+				return adjustment, err
+			}
+		}
+		if err := guard.CheckRate(moved[user.ID], adjustment.Delta); err != nil {
+			return adjustment, err
+		}
+	}
+	tiers[user.ID] = u.tierPolicy(false).TierAfter(tier, balance+adjustment.Delta)
+	return adjustment, nil
+}
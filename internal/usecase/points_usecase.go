@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"errors"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// pointsUseCase implements the PointsUseCase interface
+type pointsUseCase struct {
+	userRepo domain.UserRepository
+	txnRepo  domain.PointsTransactionRepository
+}
+
+// NewPointsUseCase creates a new points use case
+func NewPointsUseCase(userRepo domain.UserRepository, txnRepo domain.PointsTransactionRepository) domain.PointsUseCase {
+	return &pointsUseCase{
+		userRepo: userRepo,
+		txnRepo:  txnRepo,
+	}
+}
+
+// GetHistory retrieves a user's points ledger, oldest first. The balance of
+// each transaction is the user's running total after it.
+func (u *pointsUseCase) GetHistory(userID uint) ([]domain.PointsTransaction, error) {
+	if userID == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if _, err := u.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	return u.txnRepo.ListByUser(userID)
+}
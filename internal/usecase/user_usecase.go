@@ -1,22 +1,91 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
+	"kbtg.tech/ai-backend-workshop/internal/config"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
 	"kbtg.tech/ai-backend-workshop/pkg/database"
+	"kbtg.tech/ai-backend-workshop/pkg/events"
+	"kbtg.tech/ai-backend-workshop/pkg/notify"
 )
 
+// Leaderboard size limits
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// defaultMembershipType is the tier new users start in
+const defaultMembershipType = "Bronze"
+
+// maxEmailsExistBatch caps how many emails can be checked in one request
+const maxEmailsExistBatch = 500
+
+// maxMembershipIDBatch caps how many membership IDs can be fetched in one request
+const maxMembershipIDBatch = 500
+
+// maxNormalizeBatch caps how many emails and phone numbers can be normalized in one request
+const maxNormalizeBatch = 1000
+
+// defaultIDAttempts is used when no membership ID retry limit is configured
+const defaultIDAttempts = 5
+
+// defaultIdempotencyKeyTTL is used when no idempotency key TTL is configured
+const defaultIdempotencyKeyTTL = 24 * time.Hour
+
 // userUseCase implements the UserUseCase interface
 type userUseCase struct {
-	userRepo domain.UserRepository
+	userRepo   domain.UserRepository
+	config     *config.Config
+	notifier   domain.Notifier
+	events     domain.EventPublisher
+	generateID func() string
+}
+
+// Option customizes a user use case
+type Option func(*userUseCase)
+
+// WithNotifier sends each new user a welcome message through notifier
+func WithNotifier(notifier domain.Notifier) Option {
+	return func(u *userUseCase) {
+		u.notifier = notifier
+	}
+}
+
+// WithEventPublisher publishes user lifecycle events through publisher
+func WithEventPublisher(publisher domain.EventPublisher) Option {
+	return func(u *userUseCase) {
+		u.events = publisher
+	}
 }
 
-// NewUserUseCase creates a new user use case
-func NewUserUseCase(userRepo domain.UserRepository) domain.UserUseCase {
-	return &userUseCase{
+// NewUserUseCase creates a new user use case. New users are sent no welcome
+// message unless a notifier is given with WithNotifier, and events go to an
+// in-memory bus with no subscribers unless a publisher is given with WithEventPublisher.
+func NewUserUseCase(userRepo domain.UserRepository, cfg *config.Config, opts ...Option) domain.UserUseCase {
+	useCase := &userUseCase{
 		userRepo: userRepo,
+		config:   cfg,
+		notifier: notify.Noop{},
+		events:   events.NewBus(),
+	}
+	useCase.generateID = func() string {
+		return database.GenerateMembershipID(useCase.config.MembershipIDNamespace)
+	}
+	for _, opt := range opts {
+		opt(useCase)
 	}
+	return useCase
 }
 
 // GetAllUsers retrieves all users
@@ -24,6 +93,68 @@ func (u *userUseCase) GetAllUsers() ([]domain.User, error) {
 	return u.userRepo.GetAll()
 }
 
+// sortableFields lists the fields users can be sorted by
+var sortableFields = map[string]bool{
+	"id":              true,
+	"points":          true,
+	"join_date":       true,
+	"last_name":       true,
+	"membership_type": true,
+}
+
+// ListUsers retrieves one page of users along with the total number of users
+func (u *userUseCase) ListUsers(opts domain.UserListOptions) ([]domain.User, int64, error) {
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.SortBy != "" && !sortableFields[opts.SortBy] {
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q, use one of id, points, join_date, last_name, membership_type", domain.ErrInvalidSort, opts.SortBy)
+	}
+	for _, membershipType := range opts.MembershipTypes {
+		if err := u.checkMembershipType(membershipType); err != nil {
+			return nil, 0, err
+		}
+	}
+	if opts.Status != "" {
+		if err := checkStatus(opts.Status); err != nil {
+			return nil, 0, err
+		}
+	}
+	opts.Order = strings.ToLower(opts.Order)
+	if opts.Order != "" && opts.Order != domain.SortOrderAsc && opts.Order != domain.SortOrderDesc {
+		return nil, 0, fmt.Errorf("%w: order must be asc or desc", domain.ErrInvalidSort)
+	}
+	return u.userRepo.List(opts)
+}
+
+// SearchUsers retrieves one page of users whose names or email contain query
+func (u *userUseCase) SearchUsers(query string, opts domain.UserListOptions) ([]domain.User, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, domain.ErrEmptySearchQuery
+	}
+	return u.userRepo.Search(query, opts)
+}
+
+// GetUserChanges returns the users changed after since, oldest change first, and
+// the cursor to pass as since on the next poll
+func (u *userUseCase) GetUserChanges(since time.Time) ([]domain.UserChange, time.Time, error) {
+	users, err := u.userRepo.ListChangedSince(since)
+	if err != nil {
+		return nil, since, err
+	}
+
+	changes := make([]domain.UserChange, len(users))
+	next := since
+	for i, user := range users {
+		changes[i] = domain.UserChange{User: user, Deleted: user.DeletedAt.Valid}
+		if changedAt := user.ChangedAt(); changedAt.After(next) {
+			next = changedAt
+		}
+	}
+	return changes, next, nil
+}
+
 // GetUserByID retrieves a user by ID
 func (u *userUseCase) GetUserByID(id uint) (*domain.User, error) {
 	if id == 0 {
@@ -32,41 +163,236 @@ func (u *userUseCase) GetUserByID(id uint) (*domain.User, error) {
 	return u.userRepo.GetByID(id)
 }
 
+// GetUserByMembershipID retrieves a user by the membership ID printed on their
+// card. The ID is trimmed and upper-cased as in GetUsersByMembershipIDs.
+func (u *userUseCase) GetUserByMembershipID(membershipID string) (*domain.User, error) {
+	membershipID = normalizeMembershipID(membershipID)
+	if membershipID == "" {
+		return nil, domain.ErrInvalidMembershipID
+	}
+	return u.userRepo.GetByMembershipID(membershipID)
+}
+
+// GetUsersByMembershipIDs fetches the users holding the given membership IDs, in
+// request order, along with the IDs no user holds. IDs are trimmed and upper-cased
+// and must be well-formed.
+func (u *userUseCase) GetUsersByMembershipIDs(membershipIDs []string) ([]domain.User, []string, error) {
+	if len(membershipIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one membership ID is required", domain.ErrInvalidBatch)
+	}
+	if len(membershipIDs) > maxMembershipIDBatch {
+		return nil, nil, fmt.Errorf("%w: at most %d membership IDs can be fetched at once", domain.ErrInvalidBatch, maxMembershipIDBatch)
+	}
+
+	seen := make(map[string]bool, len(membershipIDs))
+	normalized := make([]string, 0, len(membershipIDs))
+	for _, id := range membershipIDs {
+		id = normalizeMembershipID(id)
+		if !database.ValidMembershipID(id, u.config.MembershipIDNamespace) {
+			return nil, nil, fmt.Errorf("%w: invalid membership ID %q", domain.ErrInvalidBatch, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			normalized = append(normalized, id)
+		}
+	}
+
+	users, err := u.userRepo.GetByMembershipIDs(normalized)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]domain.User, len(users))
+	for _, user := range users {
+		byID[user.MembershipID] = user
+	}
+
+	found := make([]domain.User, 0, len(users))
+	notFound := []string{}
+	for _, id := range normalized {
+		if user, ok := byID[id]; ok {
+			found = append(found, user)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+	return found, notFound, nil
+}
+
 // CreateUser creates a new user
 func (u *userUseCase) CreateUser(req domain.CreateUserRequest) (*domain.User, error) {
-	// Validate required fields
-	if req.FirstName == "" || req.LastName == "" || req.Email == "" {
-		return nil, errors.New("first name, last name, and email are required")
+	user, err := u.newUser(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.userRepo.Create(user, u.config.WelcomeBonusPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	u.publish(domain.EventUserCreated, user.ID)
+	u.sendWelcome(user)
+	return user, nil
+}
+
+// publish announces a stored change to the user with the given ID
+func (u *userUseCase) publish(eventType string, userID uint) {
+	u.events.Publish(domain.UserEvent{ID: newEventID(), Type: eventType, UserID: userID, OccurredAt: time.Now()})
+}
+
+// newEventID returns a random 128-bit event ID in hex
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// crypto/rand only fails when the system's random source is unusable
+		panic(fmt.Sprintf("generate event ID: %v", err))
+	}
+	return hex.EncodeToString(id)
+}
+
+// sendWelcome sends a newly stored user their welcome message. A failure is
+// logged rather than returned, as the user has already been created.
+func (u *userUseCase) sendWelcome(user *domain.User) {
+	if err := u.notifier.SendWelcome(user); err != nil {
+		slog.Warn("welcome notification failed", "user_id", user.ID, "error", err)
+	}
+}
+
+// CreateUserIdempotent creates a user like CreateUser, recording key against it.
+// A repeat of the key within the configured TTL returns the user it created,
+// with replayed set, instead of creating another.
+func (u *userUseCase) CreateUserIdempotent(key string, req domain.CreateUserRequest) (*domain.User, bool, error) {
+	ttl := u.config.IdempotencyKeyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+	since := time.Now().Add(-ttl)
+	hash := requestHash(req)
+
+	if user, err := u.replayIdempotencyKey(key, hash, since); user != nil || err != nil {
+		return user, user != nil, err
+	}
+
+	user, err := u.newUser(req)
+	if err == nil {
+		err = u.userRepo.CreateWithIdempotencyKey(user, u.config.WelcomeBonusPoints, &domain.IdempotencyKey{Key: key, RequestHash: hash}, since)
+	}
+	if err != nil {
+		// A concurrent request with the same key may have won the race
+		if replayed, replayErr := u.replayIdempotencyKey(key, hash, since); replayed != nil || replayErr != nil {
+			return replayed, replayed != nil, replayErr
+		}
+		return nil, false, err
+	}
+	u.publish(domain.EventUserCreated, user.ID)
+	u.sendWelcome(user)
+	return user, false, nil
+}
+
+// replayIdempotencyKey returns the user created with key since the given time,
+// or nil when the key has not been used
+func (u *userUseCase) replayIdempotencyKey(key, hash string, since time.Time) (*domain.User, error) {
+	recorded, err := u.userRepo.GetIdempotencyKey(key, since)
+	if err != nil || recorded == nil {
+		return nil, err
+	}
+	if recorded.RequestHash != hash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	user, err := u.userRepo.GetByIDFromPrimary(recorded.UserID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrIdempotentUserDeleted
+	}
+	return user, err
+}
+
+// requestHash fingerprints a create request so a reused idempotency key can be
+// told apart from a retry
+func requestHash(req domain.CreateUserRequest) string {
+	body, _ := json.Marshal(req)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// newUser validates req and builds the user it describes, ready to be stored
+func (u *userUseCase) newUser(req domain.CreateUserRequest) (*domain.User, error) {
+	// Emails are stored canonically so case variants count as duplicates
+	req.Email = normalizeEmail(req.Email)
+	if err := validateStruct(req); err != nil {
+		return nil, err
+	}
+	if req.MembershipType != "" {
+		if err := u.checkMembershipType(req.MembershipType); err != nil {
+			return nil, err
+		}
 	}
 
 	// Check if user with email already exists
-	existingUser, _ := u.userRepo.GetByEmail(req.Email)
-	if existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+	if err := u.checkEmailAvailable(req.Email); err != nil {
+		return nil, err
+	}
+
+	membershipID, err := u.newMembershipID()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create new user
 	user := &domain.User{
-		FirstName:      req.FirstName,
-		LastName:       req.LastName,
+		FirstName:      normalizeName(req.FirstName, u.config.NameCase),
+		LastName:       normalizeName(req.LastName, u.config.NameCase),
 		Email:          req.Email,
-		Phone:          req.Phone,
+		Phone:          normalizePhone(req.Phone),
 		MembershipType: req.MembershipType,
 		Points:         req.Points,
-		MembershipID:   database.GenerateMembershipID(),
+		MembershipID:   membershipID,
+		Status:         domain.UserStatusActive,
 	}
 
+	// Grant the welcome bonus on signup
+	user.Points += u.config.WelcomeBonusPoints
+
 	// Set default membership type if not provided
 	if user.MembershipType == "" {
-		user.MembershipType = "Bronze"
+		user.MembershipType = defaultMembershipType
 	}
+	user.MembershipType = u.promotedTier(user.MembershipType, user.Points, false)
+	return user, nil
+}
 
-	err := u.userRepo.Create(user)
+// checkEmailAvailable rejects an email another user holds. Soft-deleted users
+// keep their email reserved unless the config frees it.
+func (u *userUseCase) checkEmailAvailable(email string) error {
+	taken, err := u.userRepo.EmailInUse(email, !u.config.UniqueExcludesDeleted)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if taken {
+		return domain.ErrEmailTaken
+	}
+	return nil
+}
+
+// newMembershipID generates membership IDs until one that was never issued is
+// found or the retry limit is hit. IDs of deleted users and revoked cards are
+// never reused, so a lost card cannot verify as someone else.
+func (u *userUseCase) newMembershipID() (string, error) {
+	attempts := u.config.MembershipIDMaxAttempts
+	if attempts <= 0 {
+		attempts = defaultIDAttempts
 	}
 
-	return user, nil
+	for i := 0; i < attempts; i++ {
+		id := u.generateID()
+		inUse, err := u.userRepo.MembershipIDInUse(id)
+		if err != nil {
+			return "", err
+		}
+		if !inUse {
+			return id, nil
+		}
+	}
+	return "", domain.ErrCouldNotGenerateID
 }
 
 // UpdateUser updates an existing user
@@ -74,47 +400,300 @@ func (u *userUseCase) UpdateUser(id uint, req domain.UpdateUserRequest) (*domain
 	if id == 0 {
 		return nil, errors.New("invalid user ID")
 	}
+	req.Email = normalizeEmail(req.Email)
+	if err := validateStruct(req); err != nil {
+		return nil, err
+	}
+	// Phones are stored as their digits, so "081-234-5678" leaves "0812345678" unchanged
+	if req.Phone != nil {
+		phone := normalizePhone(*req.Phone)
+		req.Phone = &phone
+	}
+	if u.config.UpdateSemantics == config.UpdateSemanticsReplace {
+		var err error
+		if req, err = replaceUpdate(req); err != nil {
+			return nil, err
+		}
+	}
 
 	// Get existing user
-	user, err := u.userRepo.GetByID(id)
+	user, err := u.userRepo.GetByIDFromPrimary(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := u.checkImmutableFields(user, req); err != nil {
+		return nil, err
+	}
+
 	// Check if email is being changed to an existing email
 	if req.Email != "" && req.Email != user.Email {
-		existingUser, _ := u.userRepo.GetByEmail(req.Email)
-		if existingUser != nil {
-			return nil, errors.New("user with this email already exists")
+		if err := u.checkEmailAvailable(req.Email); err != nil {
+			return nil, err
 		}
 		user.Email = req.Email
 	}
 
 	// Update fields if provided
 	if req.FirstName != "" {
-		user.FirstName = req.FirstName
+		user.FirstName = normalizeName(req.FirstName, u.config.NameCase)
 	}
 	if req.LastName != "" {
-		user.LastName = req.LastName
+		user.LastName = normalizeName(req.LastName, u.config.NameCase)
 	}
-	if req.Phone != "" {
-		user.Phone = req.Phone
+	if req.Phone != nil {
+		user.Phone = *req.Phone
 	}
 	if req.MembershipType != "" {
+		if err := u.checkMembershipType(req.MembershipType); err != nil {
+			return nil, err
+		}
+		if u.config.DirectTierEditsDisabled && req.MembershipType != user.MembershipType {
+			return nil, domain.ErrDirectTierEditDenied
+		}
+		if err := u.checkTierTransition(user.MembershipType, req.MembershipType); err != nil {
+			return nil, err
+		}
 		user.MembershipType = req.MembershipType
 	}
-	if req.Points != 0 {
-		user.Points = req.Points
+	if req.Points != nil {
+		delta, err := u.pointsGuard().Apply(user.MembershipType, user.Points, *req.Points-user.Points)
+		if err != nil {
+			return nil, err
+		}
+		user.Points += delta
+		if req.MembershipType == "" {
+			user.MembershipType = u.promotedTier(user.MembershipType, user.Points, req.AllowDowngrade)
+		}
 	}
 
-	err = u.userRepo.Update(user)
+	err = u.userRepo.Update(user, u.pointsGuard())
 	if err != nil {
 		return nil, err
 	}
 
+	u.publish(domain.EventUserUpdated, user.ID)
 	return user, nil
 }
 
+// replacedUser holds the fields a replace update must always send
+type replacedUser struct {
+	FirstName string `json:"first_name" validate:"required"`
+	LastName  string `json:"last_name" validate:"required"`
+	Email     string `json:"email" validate:"required"`
+}
+
+// replaceUpdate applies replace semantics to an update request: the names and
+// email are required, and an omitted phone or points value clears the field.
+// Membership type still only changes when sent, as tier moves follow their own policy.
+func replaceUpdate(req domain.UpdateUserRequest) (domain.UpdateUserRequest, error) {
+	if err := validateStruct(replacedUser{FirstName: req.FirstName, LastName: req.LastName, Email: req.Email}); err != nil {
+		return req, err
+	}
+	if req.Phone == nil {
+		req.Phone = new(string)
+	}
+	if req.Points == nil {
+		req.Points = new(int)
+	}
+	return req, nil
+}
+
+// SetPoints sets a user's points to an absolute value and moves them to the tier
+// that balance qualifies for, up or down. Users in a tier outside the built-in
+// ladder keep it.
+func (u *userUseCase) SetPoints(id uint, points int) (*domain.User, *domain.PointsTransaction, error) {
+	if id == 0 {
+		return nil, nil, errors.New("invalid user ID")
+	}
+	if points < 0 {
+		return nil, nil, domain.ErrNegativePoints
+	}
+
+	return u.setPoints(id, points, u.tierPolicy(true), domain.PointsReasonSet, u.pointsRateGuard())
+}
+
+// setPoints stores a user's new balance and the tier tiers decides on, checked
+// against guard, publishing the update
+func (u *userUseCase) setPoints(id uint, points int, tiers domain.TierPolicy, reason string, guard domain.PointsGuard) (*domain.User, *domain.PointsTransaction, error) {
+	user, txn, err := u.userRepo.SetPoints(id, points, tiers, reason, guard)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.publish(domain.EventUserUpdated, id)
+	return user, txn, nil
+}
+
+// tierThresholds returns the configured tier thresholds, falling back to the
+// built-in threshold for any tier left unset
+func (u *userUseCase) tierThresholds() domain.TierThresholds {
+	thresholds := domain.DefaultTierThresholds
+	if u.config.SilverMinPoints > 0 {
+		thresholds.SilverMinPoints = u.config.SilverMinPoints
+	}
+	if u.config.GoldMinPoints > 0 {
+		thresholds.GoldMinPoints = u.config.GoldMinPoints
+	}
+	return thresholds
+}
+
+// tierPolicy returns the rule points changes move users between tiers by,
+// derived with the configured thresholds
+func (u *userUseCase) tierPolicy(allowDowngrade bool) domain.TierPolicy {
+	return domain.TierPolicy{Thresholds: u.tierThresholds(), AllowDowngrade: allowDowngrade}
+}
+
+// membershipTypes returns the configured tiers, lowest first, falling back to the built-in tiers
+func (u *userUseCase) membershipTypes() []string {
+	if len(u.config.MembershipTypes) > 0 {
+		return u.config.MembershipTypes
+	}
+	return domain.MembershipTypes
+}
+
+// checkMembershipType rejects a tier outside the configured tiers, listing the valid ones
+func (u *userUseCase) checkMembershipType(t string) error {
+	types := u.membershipTypes()
+	if domain.TierRank(types, t) < 0 {
+		return fmt.Errorf("%w: %s, must be one of %s", domain.ErrInvalidMembershipType, t, strings.Join(types, ", "))
+	}
+	return nil
+}
+
+// promotedTier returns the tier a user in current with the given points should be in.
+// Users are moved up to the tier their points qualify for, and only moved down
+// when allowDowngrade is set; configured tiers outside the built-in ladder are kept.
+func (u *userUseCase) promotedTier(current string, points int, allowDowngrade bool) string {
+	return u.tierPolicy(allowDowngrade).TierAfter(current, points)
+}
+
+// AddPoints moves a user's points by delta, which may be negative, and returns the updated user
+func (u *userUseCase) AddPoints(id uint, delta int) (*domain.User, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if err := u.userRepo.AddPoints(id, delta, u.tierPolicy(false), u.pointsGuard()); err != nil {
+		return nil, err
+	}
+	u.publish(domain.EventUserUpdated, id)
+	return u.userRepo.GetByIDFromPrimary(id)
+}
+
+// pointsGuard returns the configured limits the repository checks balance changes against
+func (u *userUseCase) pointsGuard() domain.PointsGuard {
+	guard := u.pointsRateGuard()
+	guard.Floors = u.config.PointsFloors
+	guard.ClampToFloor = u.config.PointsFloorPolicy == config.PointsFloorClamp
+	return guard
+}
+
+// pointsRateGuard returns a guard holding only the configured rate limit, for
+// absolute balance changes that tier floors do not apply to
+func (u *userUseCase) pointsRateGuard() domain.PointsGuard {
+	return domain.PointsGuard{RateLimit: u.config.PointsRateLimit, RateWindow: u.config.PointsRateWindow}
+}
+
+// ResetUser returns a user's points and tier to what a new user starts with, keeping identity fields
+func (u *userUseCase) ResetUser(id uint) (*domain.User, *domain.PointsTransaction, error) {
+	if id == 0 {
+		return nil, nil, errors.New("invalid user ID")
+	}
+
+	return u.setPoints(id, u.config.WelcomeBonusPoints, domain.TierPolicy{Fixed: defaultMembershipType}, domain.PointsReasonReset, domain.PointsGuard{})
+}
+
+// GetTierHistory retrieves the chronological tier changes of a user
+func (u *userUseCase) GetTierHistory(id uint) ([]domain.TierChange, error) {
+	if _, err := u.GetUserByID(id); err != nil {
+		return nil, err
+	}
+
+	return u.userRepo.GetTierHistory(id)
+}
+
+// fieldChanges reports, per JSON field name, whether an update request changes that field of a user.
+// Add an entry here to make a new field usable in the immutable fields setting.
+var fieldChanges = map[string]func(user *domain.User, req domain.UpdateUserRequest) bool{
+	"first_name": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.FirstName != "" && req.FirstName != user.FirstName
+	},
+	"last_name": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.LastName != "" && req.LastName != user.LastName
+	},
+	"email": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.Email != "" && req.Email != user.Email
+	},
+	"phone": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.Phone != nil && *req.Phone != user.Phone
+	},
+	"membership_type": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.MembershipType != "" && req.MembershipType != user.MembershipType
+	},
+	"points": func(user *domain.User, req domain.UpdateUserRequest) bool {
+		return req.Points != nil && *req.Points != user.Points
+	},
+}
+
+// checkImmutableFields rejects updates that change any field configured as immutable
+func (u *userUseCase) checkImmutableFields(user *domain.User, req domain.UpdateUserRequest) error {
+	for _, field := range u.config.ImmutableFields {
+		if changed, ok := fieldChanges[field]; ok && changed(user, req) {
+			return fmt.Errorf("%w: %s", domain.ErrFieldImmutable, field)
+		}
+	}
+	return nil
+}
+
+// checkTierTransition enforces the configured policy on manual membership type changes
+func (u *userUseCase) checkTierTransition(from, to string) error {
+	if from == to || len(u.config.TierTransitions) == 0 {
+		return nil
+	}
+	for _, allowed := range u.config.TierTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", domain.ErrTierTransitionNotAllowed, from, to)
+}
+
+// RestoreUser brings back a soft-deleted user
+func (u *userUseCase) RestoreUser(id uint) (*domain.User, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if err := u.userRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	u.publish(domain.EventUserUpdated, id)
+	return u.userRepo.GetByIDFromPrimary(id)
+}
+
+// UpdateStatus moves a user to the given account status, suspending or reactivating them
+func (u *userUseCase) UpdateStatus(id uint, status string) (*domain.User, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if err := checkStatus(status); err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.UpdateStatus(id, status); err != nil {
+		return nil, err
+	}
+	u.publish(domain.EventUserUpdated, id)
+	return u.userRepo.GetByIDFromPrimary(id)
+}
+
+// checkStatus rejects an account status outside domain.UserStatuses
+func checkStatus(status string) error {
+	for _, valid := range domain.UserStatuses {
+		if status == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s, must be one of %s", domain.ErrInvalidStatus, status, strings.Join(domain.UserStatuses, ", "))
+}
+
 // DeleteUser deletes a user
 func (u *userUseCase) DeleteUser(id uint) error {
 	if id == 0 {
@@ -122,10 +701,324 @@ func (u *userUseCase) DeleteUser(id uint) error {
 	}
 
 	// Check if user exists
-	_, err := u.userRepo.GetByID(id)
+	_, err := u.userRepo.GetByIDFromPrimary(id)
 	if err != nil {
 		return err
 	}
 
-	return u.userRepo.Delete(id)
+	if err := u.userRepo.Delete(id); err != nil {
+		return err
+	}
+	u.publish(domain.EventUserDeleted, id)
+	return nil
+}
+
+// DeleteUserIfUnmodified deletes a user only if it is still at one of the given
+// revisions, failing with ErrUserModified otherwise
+func (u *userUseCase) DeleteUserIfUnmodified(id uint, revisions []time.Time) error {
+	if id == 0 {
+		return errors.New("invalid user ID")
+	}
+	if len(revisions) == 0 {
+		return domain.ErrUserModified
+	}
+	if err := u.userRepo.DeleteIfUnmodified(id, revisions); err != nil {
+		return err
+	}
+	u.publish(domain.EventUserDeleted, id)
+	return nil
+}
+
+// DeleteUsers deletes several users and reports the outcome per ID
+func (u *userUseCase) DeleteUsers(ids []uint) ([]domain.BatchDeleteResult, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: at least one user ID is required", domain.ErrInvalidBatch)
+	}
+
+	deleted, err := u.userRepo.DeleteByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BatchDeleteResult, len(ids))
+	for i, id := range ids {
+		results[i] = domain.BatchDeleteResult{ID: id}
+	}
+
+	deletedSet := make(map[uint]bool, len(deleted))
+	for _, id := range deleted {
+		deletedSet[id] = true
+		u.publish(domain.EventUserDeleted, id)
+	}
+	for i := range results {
+		if deletedSet[results[i].ID] {
+			results[i].Status = domain.BatchDeleteDeleted
+		} else {
+			results[i].Status = domain.BatchDeleteNotFound
+		}
+	}
+
+	return results, nil
+}
+
+// GetTotalPoints returns the summed points of all users, optionally filtered by membership type
+func (u *userUseCase) GetTotalPoints(membershipType string) (int64, error) {
+	return u.userRepo.SumPoints(membershipType)
+}
+
+// GetTierStats returns per-tier user statistics ordered from the lowest tier,
+// with any unrecognised tiers last
+func (u *userUseCase) GetTierStats(membershipType string) ([]domain.TierStats, error) {
+	stats, err := u.userRepo.GetTierStats(membershipType)
+	if err != nil {
+		return nil, err
+	}
+	u.sortTierStats(stats)
+	return stats, nil
+}
+
+// GetUserStats returns the user count and points totals, optionally filtered by
+// membership type. Every tier in scope has a count, even when it has no users.
+func (u *userUseCase) GetUserStats(membershipType string) (*domain.UserStats, error) {
+	stats, err := u.userRepo.GetUserStats(membershipType)
+	if err != nil {
+		return nil, err
+	}
+	u.sortTierStats(stats.Tiers)
+
+	stats.TierCounts = make(map[string]int64)
+	if membershipType != "" {
+		stats.TierCounts[membershipType] = 0
+	} else {
+		for _, t := range u.membershipTypes() {
+			stats.TierCounts[t] = 0
+		}
+	}
+	for _, tier := range stats.Tiers {
+		stats.TierCounts[tier.MembershipType] = tier.Count
+	}
+	if stats.Tiers == nil {
+		stats.Tiers = []domain.TierStats{}
+	}
+	return stats, nil
+}
+
+// sortTierStats orders stats from the lowest tier, with any unrecognised tiers last
+func (u *userUseCase) sortTierStats(stats []domain.TierStats) {
+	types := u.membershipTypes()
+	rank := func(t string) int {
+		if i := domain.TierRank(types, t); i >= 0 {
+			return i
+		}
+		return len(types)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return rank(stats[i].MembershipType) < rank(stats[j].MembershipType)
+	})
+}
+
+// GetLeaderboard ranks users by points, either across all tiers or within one.
+// Users below the minimum points never appear and do not take up a rank.
+func (u *userUseCase) GetLeaderboard(opts domain.LeaderboardOptions) ([]domain.LeaderboardEntry, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = defaultLeaderboardLimit
+	}
+	if opts.Limit > maxLeaderboardLimit {
+		opts.Limit = maxLeaderboardLimit
+	}
+	if opts.MinPoints == nil {
+		minPoints := u.config.LeaderboardMinPoints
+		opts.MinPoints = &minPoints
+	}
+
+	users, err := u.userRepo.GetTopByPoints(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ranks restart at 1 within the filtered set
+	entries := make([]domain.LeaderboardEntry, len(users))
+	for i, user := range users {
+		entries[i] = domain.LeaderboardEntry{Rank: i + 1, User: user}
+	}
+	return entries, nil
+}
+
+// ReissueInvalidMembershipIDs gives a new membership ID to every user whose
+// current ID does not match the configured format, leaving valid IDs untouched
+func (u *userUseCase) ReissueInvalidMembershipIDs() ([]domain.MembershipIDChange, error) {
+	users, err := u.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []domain.MembershipIDChange{}
+	for _, user := range users {
+		if database.ValidMembershipID(user.MembershipID, u.config.MembershipIDNamespace) {
+			continue
+		}
+
+		membershipID, err := u.newMembershipID()
+		if err != nil {
+			return changes, err
+		}
+		if err := u.userRepo.UpdateMembershipID(user.ID, membershipID); err != nil {
+			return changes, err
+		}
+		u.publish(domain.EventUserUpdated, user.ID)
+		changes = append(changes, domain.MembershipIDChange{
+			UserID: user.ID,
+			OldID:  user.MembershipID,
+			NewID:  membershipID,
+		})
+	}
+
+	return changes, nil
+}
+
+// ReissueMembershipID replaces the membership ID of a lost card with a new one,
+// revoking the old ID so it no longer verifies
+func (u *userUseCase) ReissueMembershipID(id uint) (*domain.MembershipIDChange, error) {
+	if id == 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	membershipID, err := u.newMembershipID()
+	if err != nil {
+		return nil, err
+	}
+	change, err := u.userRepo.ReplaceMembershipID(id, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	u.publish(domain.EventUserUpdated, id)
+	return change, nil
+}
+
+// VerifyMembershipID returns the user holding a membership ID. IDs from reissued
+// cards fail with ErrMembershipIDRevoked.
+func (u *userUseCase) VerifyMembershipID(membershipID string) (*domain.User, error) {
+	if user, _ := u.userRepo.GetByMembershipID(membershipID); user != nil {
+		return user, nil
+	}
+
+	revoked, err := u.userRepo.IsMembershipIDRevoked(membershipID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, domain.ErrMembershipIDRevoked
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// CheckEmailsExist reports for each normalized email whether a user already has
+// it, counting deleted users exactly as creating a user would
+func (u *userUseCase) CheckEmailsExist(emails []string) (map[string]bool, error) {
+	if len(emails) == 0 {
+		return nil, fmt.Errorf("%w: at least one email is required", domain.ErrInvalidBatch)
+	}
+	if len(emails) > maxEmailsExistBatch {
+		return nil, fmt.Errorf("%w: at most %d emails can be checked at once", domain.ErrInvalidBatch, maxEmailsExistBatch)
+	}
+
+	exists := make(map[string]bool, len(emails))
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		email = normalizeEmail(email)
+		if _, seen := exists[email]; !seen {
+			exists[email] = false
+			normalized = append(normalized, email)
+		}
+	}
+
+	found, err := u.userRepo.FindExistingEmails(normalized, !u.config.UniqueExcludesDeleted)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range found {
+		exists[email] = true
+	}
+	return exists, nil
+}
+
+// ReconcileTiers finds users whose stored tier differs from the tier their points
+// imply. In correct mode each mismatch is also fixed; otherwise it is only reported.
+// Configured tiers outside the built-in ladder, such as Platinum, are not derived
+// from points and are left alone.
+func (u *userUseCase) ReconcileTiers() ([]domain.TierMismatch, error) {
+	users, err := u.userRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := []domain.TierMismatch{}
+	for _, user := range users {
+		if domain.TierRank(domain.MembershipTypes, user.MembershipType) < 0 {
+			continue
+		}
+		expected := u.tierThresholds().TierFor(user.Points)
+		if user.MembershipType == expected {
+			continue
+		}
+
+		mismatch := domain.TierMismatch{
+			UserID:   user.ID,
+			Points:   user.Points,
+			Stored:   user.MembershipType,
+			Expected: expected,
+		}
+		if u.config.TierReconcileMode == config.ReconcileModeCorrect {
+			if err := u.userRepo.SetMembershipType(user.ID, expected, domain.TierTriggerReconcile); err != nil {
+				return mismatches, err
+			}
+			u.publish(domain.EventUserUpdated, user.ID)
+			mismatch.Corrected = true
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches, nil
+}
+
+// SimulateTiers reports how tier sizes would change if tiers were derived with the
+// given thresholds, for every configured tier. Users in tiers outside the built-in
+// ladder keep their tier. Nothing is updated.
+func (u *userUseCase) SimulateTiers(thresholds domain.TierThresholds) ([]domain.TierSimulation, error) {
+	if thresholds.SilverMinPoints <= 0 || thresholds.GoldMinPoints <= thresholds.SilverMinPoints {
+		return nil, fmt.Errorf("%w: silver_min_points must be positive and below gold_min_points", domain.ErrInvalidThresholds)
+	}
+
+	moves, err := u.userRepo.CountTierMoves(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	types := u.membershipTypes()
+	simulations := make([]domain.TierSimulation, len(types))
+	index := make(map[string]int, len(types))
+	for i, membershipType := range types {
+		simulations[i].MembershipType = membershipType
+		index[membershipType] = i
+	}
+	tier := func(membershipType string) *domain.TierSimulation {
+		if i, ok := index[membershipType]; ok {
+			return &simulations[i]
+		}
+		index[membershipType] = len(simulations)
+		simulations = append(simulations, domain.TierSimulation{MembershipType: membershipType})
+		return &simulations[len(simulations)-1]
+	}
+
+	for _, move := range moves {
+		if domain.TierRank(domain.MembershipTypes, move.From) < 0 {
+			move.To = move.From
+		}
+		tier(move.From).CurrentCount += move.Count
+		tier(move.To).SimulatedCount += move.Count
+		if move.From != move.To {
+			tier(move.From).MovingOut += move.Count
+			tier(move.To).MovingIn += move.Count
+		}
+	}
+	return simulations, nil
 }
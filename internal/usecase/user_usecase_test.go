@@ -1,19 +1,27 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"kbtg.tech/ai-backend-workshop/internal/config"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
 	"kbtg.tech/ai-backend-workshop/internal/mocks"
+	"kbtg.tech/ai-backend-workshop/pkg/events"
 )
 
 func TestUserUseCase_GetAllUsers(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	expectedUsers := []domain.User{
 		{ID: 1, FirstName: "John", LastName: "Doe", Email: "john@example.com"},
@@ -34,7 +42,7 @@ func TestUserUseCase_GetAllUsers(t *testing.T) {
 func TestUserUseCase_GetAllUsers_Error(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	mockRepo.On("GetAll").Return([]domain.User{}, errors.New("database error"))
 
@@ -50,7 +58,7 @@ func TestUserUseCase_GetAllUsers_Error(t *testing.T) {
 func TestUserUseCase_GetUserByID(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	expectedUser := &domain.User{
 		ID:        1,
@@ -73,7 +81,7 @@ func TestUserUseCase_GetUserByID(t *testing.T) {
 func TestUserUseCase_GetUserByID_InvalidID(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	// Act
 	result, err := useCase.GetUserByID(0)
@@ -88,7 +96,7 @@ func TestUserUseCase_GetUserByID_InvalidID(t *testing.T) {
 func TestUserUseCase_CreateUser(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	req := domain.CreateUserRequest{
 		FirstName:      "John",
@@ -99,8 +107,9 @@ func TestUserUseCase_CreateUser(t *testing.T) {
 		Points:         100,
 	}
 
-	mockRepo.On("GetByEmail", "john@example.com").Return(nil, errors.New("user not found"))
-	mockRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
 
 	// Act
 	result, err := useCase.CreateUser(req)
@@ -111,17 +120,126 @@ func TestUserUseCase_CreateUser(t *testing.T) {
 	assert.Equal(t, req.FirstName, result.FirstName)
 	assert.Equal(t, req.LastName, result.LastName)
 	assert.Equal(t, req.Email, result.Email)
-	assert.Equal(t, req.Phone, result.Phone)
+	assert.Equal(t, "1234567890", result.Phone)
 	assert.Equal(t, req.MembershipType, result.MembershipType)
 	assert.Equal(t, req.Points, result.Points)
 	assert.NotEmpty(t, result.MembershipID)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_CreateUser_SendsWelcome(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	useCase := NewUserUseCase(mockRepo, &config.Config{}, WithNotifier(mockNotifier))
+
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+	mockNotifier.On("SendWelcome", mock.AnythingOfType("*domain.User")).Return(nil).Once()
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.NoError(t, err)
+	mockNotifier.AssertNumberOfCalls(t, "SendWelcome", 1)
+	mockNotifier.AssertCalled(t, "SendWelcome", result)
+}
+
+func TestUserUseCase_CreateUser_WelcomeFailureIgnored(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	useCase := NewUserUseCase(mockRepo, &config.Config{}, WithNotifier(mockNotifier))
+
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+	mockNotifier.On("SendWelcome", mock.AnythingOfType("*domain.User")).Return(errors.New("connection refused"))
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestUserUseCase_CreateUser_NoWelcomeOnFailure(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	mockNotifier := new(mocks.MockNotifier)
+	useCase := NewUserUseCase(mockRepo, &config.Config{}, WithNotifier(mockNotifier))
+
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(errors.New("database error"))
+
+	// Act
+	_, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.Error(t, err)
+	mockNotifier.AssertNotCalled(t, "SendWelcome", mock.Anything)
+}
+
+func TestUserUseCase_CreateUser_MembershipIDNamespace(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{MembershipIDNamespace: "PROD"})
+
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Regexp(t, `^LBK-PROD-\d{6}$`, result.MembershipID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_CreateUser_WelcomeBonus(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{WelcomeBonusPoints: 500})
+
+	req := domain.CreateUserRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+		Points:    100,
+	}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 500).Return(nil)
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 600, result.Points)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUserUseCase_CreateUser_MissingRequiredFields(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	req := domain.CreateUserRequest{
 		FirstName: "John",
@@ -133,16 +251,39 @@ func TestUserUseCase_CreateUser_MissingRequiredFields(t *testing.T) {
 	result, err := useCase.CreateUser(req)
 
 	// Assert
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrValidation)
 	assert.Nil(t, result)
-	assert.Equal(t, "first name, last name, and email are required", err.Error())
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []domain.FieldError{{Field: "last_name", Message: "is required"}}, validationErr.Fields)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_InvalidPhone(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	phone := "12345"
+
+	// Act
+	created, createErr := useCase.CreateUser(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-CALL-NOW"})
+	updated, updateErr := useCase.UpdateUser(1, domain.UpdateUserRequest{Phone: &phone})
+
+	// Assert
+	var validationErr *domain.ValidationError
+	assert.Nil(t, created)
+	assert.ErrorAs(t, createErr, &validationErr)
+	assert.Equal(t, []domain.FieldError{{Field: "phone", Message: "must contain only digits and separators"}}, validationErr.Fields)
+	assert.Nil(t, updated)
+	assert.ErrorAs(t, updateErr, &validationErr)
+	assert.Equal(t, []domain.FieldError{{Field: "phone", Message: "must have 9 to 15 digits"}}, validationErr.Fields)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUserUseCase_CreateUser_EmailExists(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	req := domain.CreateUserRequest{
 		FirstName: "John",
@@ -150,8 +291,7 @@ func TestUserUseCase_CreateUser_EmailExists(t *testing.T) {
 		Email:     "john@example.com",
 	}
 
-	existingUser := &domain.User{ID: 1, Email: "john@example.com"}
-	mockRepo.On("GetByEmail", "john@example.com").Return(existingUser, nil)
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(true, nil)
 
 	// Act
 	result, err := useCase.CreateUser(req)
@@ -163,10 +303,28 @@ func TestUserUseCase_CreateUser_EmailExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_CreateUser_NormalizesEmail(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+
+	// Act
+	result, err := useCase.CreateUser(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: " John@Example.COM "})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", result.Email)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUserUseCase_UpdateUser(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	existingUser := &domain.User{
 		ID:        1,
@@ -176,13 +334,14 @@ func TestUserUseCase_UpdateUser(t *testing.T) {
 		Points:    100,
 	}
 
+	points := 200
 	updateReq := domain.UpdateUserRequest{
 		FirstName: "Jane",
-		Points:    200,
+		Points:    &points,
 	}
 
-	mockRepo.On("GetByID", uint(1)).Return(existingUser, nil)
-	mockRepo.On("Update", mock.AnythingOfType("*domain.User")).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
 
 	// Act
 	result, err := useCase.UpdateUser(1, updateReq)
@@ -197,13 +356,250 @@ func TestUserUseCase_UpdateUser(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_UpdateUser_Points(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name     string
+		points   *int
+		expected int
+	}{
+		{"omitted leaves points unchanged", nil, 100},
+		{"zero resets points", &zero, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{})
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, FirstName: "John", Points: 100}, nil)
+			mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+			// Act
+			result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{FirstName: "Jane", Points: tt.points})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Points)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUseCase_UpdateUser_Semantics(t *testing.T) {
+	zero := 0
+	tests := []struct {
+		name      string
+		semantics string
+		req       domain.UpdateUserRequest
+		points    int
+		phone     string
+	}{
+		{"merge ignores omitted points", config.UpdateSemanticsMerge, domain.UpdateUserRequest{FirstName: "Jane"}, 100, "081-234-5678"},
+		{"merge applies zero points", config.UpdateSemanticsMerge, domain.UpdateUserRequest{Points: &zero}, 0, "081-234-5678"},
+		{"replace clears omitted points", config.UpdateSemanticsReplace, domain.UpdateUserRequest{FirstName: "Jane", LastName: "Doe", Email: "john@example.com"}, 0, ""},
+		{"replace applies zero points", config.UpdateSemanticsReplace, domain.UpdateUserRequest{FirstName: "Jane", LastName: "Doe", Email: "john@example.com", Points: &zero}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{UpdateSemantics: tt.semantics})
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", Points: 100}, nil)
+			mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+			// Act
+			result, err := useCase.UpdateUser(1, tt.req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.points, result.Points)
+			assert.Equal(t, tt.phone, result.Phone)
+		})
+	}
+}
+
+func TestUserUseCase_UpdateUser_ReplaceMissingFields(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{UpdateSemantics: config.UpdateSemanticsReplace})
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{FirstName: "Jane"})
+
+	// Assert
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []domain.FieldError{
+		{Field: "last_name", Message: "is required"},
+		{Field: "email", Message: "is required"},
+	}, validationErr.Fields)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "GetByIDFromPrimary", mock.Anything)
+}
+
+func TestUserUseCase_UpdateUser_TierPromotion(t *testing.T) {
+	low, silver, gold, top := 100, 12000, 25000, 30000
+	tests := []struct {
+		name     string
+		current  string
+		req      domain.UpdateUserRequest
+		expected string
+	}{
+		{"points above silver promote to silver", "Bronze", domain.UpdateUserRequest{Points: &silver}, "Silver"},
+		{"points above gold promote to gold", "Silver", domain.UpdateUserRequest{Points: &gold}, "Gold"},
+		{"lower points keep the tier", "Gold", domain.UpdateUserRequest{Points: &low}, "Gold"},
+		{"lower points downgrade when allowed", "Gold", domain.UpdateUserRequest{Points: &silver, AllowDowngrade: true}, "Silver"},
+		{"explicit membership type wins", "Bronze", domain.UpdateUserRequest{Points: &top, MembershipType: "Silver"}, "Silver"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{SilverMinPoints: 10000, GoldMinPoints: 25000})
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, FirstName: "John", MembershipType: tt.current, Points: 8000}, nil)
+			mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+			// Act
+			result, err := useCase.UpdateUser(1, tt.req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.MembershipType)
+		})
+	}
+}
+
+func TestUserUseCase_CreateUser_TierPromotion(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{SilverMinPoints: 10000, GoldMinPoints: 25000})
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.Anything).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+
+	// Act
+	result, err := useCase.CreateUser(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", Points: 12000})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Silver", result.MembershipType)
+}
+
+func TestUserUseCase_AddPoints_ConfiguredThresholds(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{SilverMinPoints: 8000})
+	thresholds := domain.TierThresholds{SilverMinPoints: 8000, GoldMinPoints: domain.GoldMinPoints}
+	mockRepo.On("AddPoints", uint(1), 500, domain.TierPolicy{Thresholds: thresholds}, domain.PointsGuard{}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, Points: 8100, MembershipType: "Silver"}, nil)
+
+	// Act
+	result, err := useCase.AddPoints(1, 500)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Silver", result.MembershipType)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_AddPoints_Floor(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{PointsFloors: map[string]int{"Gold": 1000}, PointsFloorPolicy: config.PointsFloorClamp})
+	guard := domain.PointsGuard{Floors: map[string]int{"Gold": 1000}, ClampToFloor: true}
+	mockRepo.On("AddPoints", uint(1), -1000, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, guard).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, Points: 1000, MembershipType: "Gold"}, nil)
+
+	// Act
+	result, err := useCase.AddPoints(1, -1000)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, result.Points)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_PointsFloor(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		points       int
+		expectPoints int
+		expectErr    error
+	}{
+		{"reject below floor", config.PointsFloorReject, 200, 0, domain.ErrBelowPointsFloor},
+		{"clamp to floor", config.PointsFloorClamp, 200, 1000, nil},
+		{"deduction above floor", config.PointsFloorReject, 1200, 1200, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{PointsFloors: map[string]int{"Gold": 1000}, PointsFloorPolicy: tt.policy})
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, Points: 1500, MembershipType: "Gold"}, nil)
+			if tt.expectErr == nil {
+				mockRepo.On("Update", mock.AnythingOfType("*domain.User"), mock.AnythingOfType("domain.PointsGuard")).Return(nil)
+			}
+
+			// Act
+			result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{Points: &tt.points})
+
+			// Assert
+			if tt.expectErr != nil {
+				assert.ErrorIs(t, err, tt.expectErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectPoints, result.Points)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUseCase_UpdateUser_Phone(t *testing.T) {
+	empty, phone := "", "089-765-4321"
+	tests := []struct {
+		name     string
+		phone    *string
+		expected string
+	}{
+		{"omitted leaves phone unchanged", nil, "081-234-5678"},
+		{"empty clears phone", &empty, ""},
+		{"value replaces phone", &phone, "0897654321"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{})
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, FirstName: "John", Phone: "081-234-5678"}, nil)
+			mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+			// Act
+			result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{FirstName: "Jane", Phone: tt.phone})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Phone)
+			assert.Equal(t, "Jane", result.FirstName)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUserUseCase_DeleteUser(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
 	existingUser := &domain.User{ID: 1, Email: "john@example.com"}
-	mockRepo.On("GetByID", uint(1)).Return(existingUser, nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
 	mockRepo.On("Delete", uint(1)).Return(nil)
 
 	// Act
@@ -214,12 +610,109 @@ func TestUserUseCase_DeleteUser(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserUseCase_PublishesLifecycleEvents(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	bus := events.NewBus()
+	useCase := NewUserUseCase(mockRepo, &config.Config{}, WithEventPublisher(bus))
+
+	var received []domain.UserEvent
+	bus.Subscribe(func(event domain.UserEvent) {
+		received = append(received, event)
+	})
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.User).ID = 1
+	}).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1, FirstName: "John", Email: "john@example.com"}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+	mockRepo.On("Delete", uint(1)).Return(nil)
+	before := time.Now()
+
+	// Act
+	_, createErr := useCase.CreateUser(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"})
+	_, updateErr := useCase.UpdateUser(1, domain.UpdateUserRequest{FirstName: "Johnny"})
+	deleteErr := useCase.DeleteUser(1)
+
+	// Assert
+	assert.NoError(t, createErr)
+	assert.NoError(t, updateErr)
+	assert.NoError(t, deleteErr)
+	var types []string
+	ids := map[string]bool{}
+	for _, event := range received {
+		types = append(types, event.Type)
+		ids[event.ID] = true
+		assert.Equal(t, uint(1), event.UserID)
+		assert.False(t, event.OccurredAt.Before(before))
+		assert.Len(t, event.ID, 32)
+	}
+	assert.Equal(t, []string{domain.EventUserCreated, domain.EventUserUpdated, domain.EventUserDeleted}, types)
+	assert.Len(t, ids, len(received))
+}
+
+func TestUserUseCase_NoEventOnFailedWrite(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	bus := events.NewBus()
+	useCase := NewUserUseCase(mockRepo, &config.Config{}, WithEventPublisher(bus))
+
+	published := 0
+	bus.Subscribe(func(domain.UserEvent) { published++ })
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(&domain.User{ID: 1}, nil)
+	mockRepo.On("Delete", uint(1)).Return(errors.New("database error"))
+
+	// Act
+	err := useCase.DeleteUser(1)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Zero(t, published)
+}
+
+func TestUserUseCase_PublishesBatchUpdates(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	bus := events.NewBus()
+	useCase := NewUserUseCase(mockRepo, &config.Config{TierReconcileMode: config.ReconcileModeCorrect}, WithEventPublisher(bus))
+
+	var updated []uint
+	bus.Subscribe(func(event domain.UserEvent) {
+		assert.Equal(t, domain.EventUserUpdated, event.Type)
+		updated = append(updated, event.UserID)
+	})
+
+	mockRepo.On("GetAll").Return([]domain.User{
+		{ID: 1, MembershipID: "legacy-1", MembershipType: "Bronze", Points: 100},
+		{ID: 2, MembershipID: "LBK000002", MembershipType: "Bronze", Points: 6000},
+	}, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil).Once()
+	mockRepo.On("GetByMembershipID", "LBK000003").Return(&domain.User{ID: 3, MembershipID: "LBK000003", Points: 100}, nil)
+	mockRepo.On("UpdateMembershipID", uint(1), mock.AnythingOfType("string")).Return(nil)
+	mockRepo.On("SetMembershipType", uint(2), "Silver", domain.TierTriggerReconcile).Return(nil)
+	mockRepo.On("AdjustPoints", mock.Anything, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{}).Return(nil)
+
+	// Act
+	_, reissueErr := useCase.ReissueInvalidMembershipIDs()
+	_, reconcileErr := useCase.ReconcileTiers()
+	_, importErr := useCase.ImportPoints(strings.NewReader("membership_id,delta\nLBK000003,50\n"), domain.ImportModeAtomic)
+
+	// Assert
+	assert.NoError(t, reissueErr)
+	assert.NoError(t, reconcileErr)
+	assert.NoError(t, importErr)
+	assert.Equal(t, []uint{1, 2, 3}, updated)
+}
+
 func TestUserUseCase_DeleteUser_UserNotFound(t *testing.T) {
 	// Arrange
 	mockRepo := new(mocks.MockUserRepository)
-	useCase := NewUserUseCase(mockRepo)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
 
-	mockRepo.On("GetByID", uint(1)).Return(nil, errors.New("user not found"))
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(nil, domain.ErrUserNotFound)
 
 	// Act
 	err := useCase.DeleteUser(1)
@@ -229,3 +722,884 @@ func TestUserUseCase_DeleteUser_UserNotFound(t *testing.T) {
 	assert.Equal(t, "user not found", err.Error())
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserUseCase_RestoreUser(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	restored := &domain.User{ID: 1, FirstName: "John", Email: "john@example.com"}
+	mockRepo.On("Restore", uint(1)).Return(nil)
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(restored, nil)
+
+	// Act
+	result, err := useCase.RestoreUser(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, restored, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_RestoreUser_Conflict(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("Restore", uint(1)).Return(domain.ErrRestoreConflict)
+
+	// Act
+	result, err := useCase.RestoreUser(1)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrRestoreConflict)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "GetByIDFromPrimary", mock.Anything)
+}
+
+func TestPointsUseCase_GetHistory(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	mockTxnRepo := new(mocks.MockPointsTransactionRepository)
+	useCase := NewPointsUseCase(mockRepo, mockTxnRepo)
+	txns := []domain.PointsTransaction{
+		{ID: 1, UserID: 1, Delta: 100, Balance: 100, Reason: domain.PointsReasonInitial},
+		{ID: 2, UserID: 1, Delta: -40, Balance: 60, Reason: domain.PointsReasonAdd},
+	}
+	mockRepo.On("GetByID", uint(1)).Return(&domain.User{ID: 1, Points: 60}, nil)
+	mockTxnRepo.On("ListByUser", uint(1)).Return(txns, nil)
+
+	// Act
+	result, err := useCase.GetHistory(1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, txns, result)
+	mockRepo.AssertExpectations(t)
+	mockTxnRepo.AssertExpectations(t)
+}
+
+func TestPointsUseCase_GetHistory_UserNotFound(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	mockTxnRepo := new(mocks.MockPointsTransactionRepository)
+	useCase := NewPointsUseCase(mockRepo, mockTxnRepo)
+	mockRepo.On("GetByID", uint(1)).Return(nil, domain.ErrUserNotFound)
+
+	// Act
+	result, err := useCase.GetHistory(1)
+
+	// Assert
+	assert.EqualError(t, err, "user not found")
+	assert.Nil(t, result)
+	mockTxnRepo.AssertNotCalled(t, "ListByUser", mock.Anything)
+}
+
+func TestUserUseCase_DeleteUsers(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	mockRepo.On("DeleteByIDs", []uint{1, 2}).Return([]uint{1}, nil)
+
+	// Act
+	results, err := useCase.DeleteUsers([]uint{1, 2})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.BatchDeleteResult{
+		{ID: 1, Status: domain.BatchDeleteDeleted},
+		{ID: 2, Status: domain.BatchDeleteNotFound},
+	}, results)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_DeleteUsers_Error(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	mockRepo.On("DeleteByIDs", []uint{1}).Return(nil, errors.New("database error"))
+
+	// Act
+	results, err := useCase.DeleteUsers([]uint{1})
+
+	// Assert
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, results)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_DeleteUsers_Empty(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	// Act
+	results, err := useCase.DeleteUsers(nil)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+	assert.Nil(t, results)
+}
+
+func TestUserUseCase_CreateUser_TitleCaseNames(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{NameCase: config.NameCaseTitle})
+
+	req := domain.CreateUserRequest{
+		FirstName: "john",
+		LastName:  "ใจดี",
+		Email:     "john@example.com",
+	}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "John", result.FirstName)
+	assert.Equal(t, "ใจดี", result.LastName)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_GetLeaderboard(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	minPoints := 0
+	mockRepo.On("GetTopByPoints", domain.LeaderboardOptions{MembershipType: "Gold", Limit: 10, MinPoints: &minPoints}).Return([]domain.User{
+		{ID: 3, MembershipType: "Gold", Points: 900},
+		{ID: 1, MembershipType: "Gold", Points: 500},
+	}, nil)
+
+	// Act
+	entries, err := useCase.GetLeaderboard(domain.LeaderboardOptions{MembershipType: "Gold"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 1, entries[0].Rank)
+	assert.Equal(t, uint(3), entries[0].ID)
+	assert.Equal(t, 2, entries[1].Rank)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_CreateUser_MembershipIDCollisions(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{MembershipIDMaxAttempts: 3})
+
+	// Every candidate collides with an existing user
+	calls := 0
+	useCase.(*userUseCase).generateID = func() string {
+		calls++
+		return "LBK000001"
+	}
+
+	req := domain.CreateUserRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	}
+
+	mockRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	mockRepo.On("MembershipIDInUse", "LBK000001").Return(true, nil)
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrCouldNotGenerateID)
+	assert.Nil(t, result)
+	assert.Equal(t, 3, calls)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_AllowedTierTransition(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{
+		TierTransitions: map[string][]string{"Bronze": {"Silver"}, "Silver": {"Bronze", "Gold"}},
+	})
+
+	existingUser := &domain.User{ID: 1, FirstName: "John", MembershipType: "Bronze"}
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{MembershipType: "Silver"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Silver", result.MembershipType)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_DisallowedTierTransition(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{
+		TierTransitions: map[string][]string{"Bronze": {"Silver"}, "Silver": {"Bronze", "Gold"}},
+	})
+
+	existingUser := &domain.User{ID: 1, FirstName: "John", MembershipType: "Bronze"}
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{MembershipType: "Gold"})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrTierTransitionNotAllowed)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_ImmutableField(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{ImmutableFields: []string{"email"}})
+
+	existingUser := &domain.User{ID: 1, FirstName: "John", Email: "john@example.com"}
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{Email: "johnny@example.com"})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrFieldImmutable)
+	assert.Contains(t, err.Error(), "email")
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_ImmutableFieldUnchanged(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{ImmutableFields: []string{"email"}})
+
+	existingUser := &domain.User{ID: 1, FirstName: "John", Email: "john@example.com"}
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{Email: "john@example.com", FirstName: "Jane"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", result.FirstName)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_SetPoints(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	updated := &domain.User{ID: 1, Points: 6000, MembershipType: "Silver"}
+	txn := &domain.PointsTransaction{UserID: 1, Delta: 1000, Balance: 6000, Reason: domain.PointsReasonSet}
+	mockRepo.On("SetPoints", uint(1), 6000, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds, AllowDowngrade: true}, domain.PointsReasonSet, domain.PointsGuard{}).Return(updated, txn, nil)
+
+	// Act
+	user, result, err := useCase.SetPoints(1, 6000)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Silver", user.MembershipType)
+	assert.Equal(t, 1000, result.Delta)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_SetPoints_Negative(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	// Act
+	user, txn, err := useCase.SetPoints(1, -1)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrNegativePoints)
+	assert.Nil(t, user)
+	assert.Nil(t, txn)
+	mockRepo.AssertNotCalled(t, "SetPoints", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserUseCase_SetPoints_RateLimitExceeded(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{PointsRateLimit: 100000, PointsRateWindow: 24 * time.Hour, PointsFloors: map[string]int{"Gold": 1000}})
+	guard := domain.PointsGuard{RateLimit: 100000, RateWindow: 24 * time.Hour}
+	mockRepo.On("SetPoints", uint(1), 51000, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds, AllowDowngrade: true}, domain.PointsReasonSet, guard).Return(nil, nil, domain.ErrPointsRateExceeded)
+
+	// Act
+	user, txn, err := useCase.SetPoints(1, 51000)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrPointsRateExceeded)
+	assert.Nil(t, user)
+	assert.Nil(t, txn)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_InvalidMembershipType(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	existingUser := &domain.User{ID: 1, FirstName: "John", MembershipType: "Bronze"}
+
+	mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+
+	// Act
+	result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{MembershipType: "Diamond"})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMembershipType)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_UpdateUser_DirectTierEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled bool
+		wantErr  error
+	}{
+		{"allowed", false, nil},
+		{"denied", true, domain.ErrDirectTierEditDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{DirectTierEditsDisabled: tt.disabled})
+
+			existingUser := &domain.User{ID: 1, FirstName: "John", MembershipType: "Bronze"}
+
+			mockRepo.On("GetByIDFromPrimary", uint(1)).Return(existingUser, nil)
+			if tt.wantErr == nil {
+				mockRepo.On("Update", mock.AnythingOfType("*domain.User"), domain.PointsGuard{}).Return(nil)
+			}
+
+			// Act
+			result, err := useCase.UpdateUser(1, domain.UpdateUserRequest{MembershipType: "Gold"})
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, result)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Gold", result.MembershipType)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUseCase_CreateUser_InvalidMembershipType(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "gold"}
+
+	// Act
+	result, err := useCase.CreateUser(req)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMembershipType)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUserUseCase_CreateUser_ConfiguredMembershipTypes(t *testing.T) {
+	// Arrange
+	req := domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Platinum"}
+	defaultRepo := new(mocks.MockUserRepository)
+	defaultUseCase := NewUserUseCase(defaultRepo, &config.Config{})
+	platinumRepo := new(mocks.MockUserRepository)
+	platinumUseCase := NewUserUseCase(platinumRepo, &config.Config{MembershipTypes: []string{"Bronze", "Silver", "Gold", "Platinum"}})
+	platinumRepo.On("EmailInUse", "john@example.com", true).Return(false, nil)
+	platinumRepo.On("MembershipIDInUse", mock.AnythingOfType("string")).Return(false, nil)
+	platinumRepo.On("Create", mock.AnythingOfType("*domain.User"), 0).Return(nil)
+
+	// Act
+	_, defaultErr := defaultUseCase.CreateUser(req)
+	req.Points = 20000
+	created, platinumErr := platinumUseCase.CreateUser(req)
+
+	// Assert
+	assert.ErrorIs(t, defaultErr, domain.ErrInvalidMembershipType)
+	assert.EqualError(t, defaultErr, "invalid membership type: Platinum, must be one of Bronze, Silver, Gold")
+	defaultRepo.AssertNotCalled(t, "Create", mock.Anything)
+
+	assert.NoError(t, platinumErr)
+	assert.Equal(t, "Platinum", created.MembershipType) // not moved down to the Gold its points earn
+	platinumRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_SearchUsers(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	opts := domain.UserListOptions{Page: 1, Limit: 20}
+	expected := []domain.User{{ID: 1, FirstName: "สมชาย"}}
+	mockRepo.On("Search", "สมชาย", opts).Return(expected, int64(1), nil)
+
+	// Act
+	users, total, err := useCase.SearchUsers("  สมชาย ", opts)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, users)
+	assert.Equal(t, int64(1), total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_SearchUsers_EmptyQuery(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	// Act
+	users, _, err := useCase.SearchUsers("   ", domain.UserListOptions{Page: 1, Limit: 20})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrEmptySearchQuery)
+	assert.Nil(t, users)
+	mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestUserUseCase_ComputeFields(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	user := &domain.User{
+		ID:        1,
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+		JoinDate:  time.Now().Add(-30 * 24 * time.Hour),
+		Points:    7000,
+	}
+	mockRepo.On("CountWithMorePoints", 7000).Return(int64(2), nil)
+
+	// Act
+	computed, err := useCase.ComputeFields(user, domain.ComputedFields)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"membership_days":      30,
+		"points_to_next_tier":  3000,
+		"profile_completeness": 75,
+		"rank":                 3,
+	}, computed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ComputeFields_Unknown(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	// Act
+	computed, err := useCase.ComputeFields(&domain.User{ID: 1}, []string{"rank", "net_worth"})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrUnknownComputedField)
+	assert.Nil(t, computed)
+	mockRepo.AssertNotCalled(t, "CountWithMorePoints", mock.Anything)
+}
+
+func TestUserUseCase_GetUserStats(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("GetUserStats", "").Return(&domain.UserStats{
+		TotalUsers:    3,
+		TotalPoints:   15300,
+		AveragePoints: 5100,
+		Tiers: []domain.TierStats{
+			{MembershipType: "Gold", Count: 1, TotalPoints: 15000, AveragePoints: 15000},
+			{MembershipType: "Bronze", Count: 2, TotalPoints: 300, AveragePoints: 150},
+		},
+	}, nil)
+
+	// Act
+	stats, err := useCase.GetUserStats("")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Bronze": 2, "Silver": 0, "Gold": 1}, stats.TierCounts)
+	assert.Equal(t, "Bronze", stats.Tiers[0].MembershipType)
+	assert.Equal(t, "Gold", stats.Tiers[1].MembershipType)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_GetUserStats_Empty(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("GetUserStats", "Gold").Return(&domain.UserStats{}, nil)
+
+	// Act
+	stats, err := useCase.GetUserStats("Gold")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.UserStats{TierCounts: map[string]int64{"Gold": 0}, Tiers: []domain.TierStats{}}, stats)
+}
+
+func TestUserUseCase_GetDashboard(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	minPoints := 0
+	mockRepo.On("GetTierStats", "").Return([]domain.TierStats{
+		{MembershipType: "Gold", Count: 1, TotalPoints: 15000},
+		{MembershipType: "Bronze", Count: 2, TotalPoints: 300},
+	}, nil)
+	mockRepo.On("SumPoints", "").Return(int64(15300), nil)
+	mockRepo.On("CountJoinedSince", mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+	mockRepo.On("GetTopByPoints", domain.LeaderboardOptions{Limit: 5, MinPoints: &minPoints}).Return([]domain.User{{ID: 3, Points: 15000}}, nil)
+
+	// Act
+	dashboard, err := useCase.GetDashboard()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), dashboard.TotalUsers)
+	assert.Equal(t, map[string]int64{"Bronze": 2, "Silver": 0, "Gold": 1}, dashboard.TierCounts)
+	assert.Equal(t, int64(2), dashboard.NewThisMonth)
+	assert.Equal(t, int64(15300), dashboard.TotalPoints)
+	assert.Equal(t, []domain.LeaderboardEntry{{Rank: 1, User: domain.User{ID: 3, Points: 15000}}}, dashboard.TopUsers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_GetDashboard_Error(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("GetTierStats", "").Return([]domain.TierStats{}, nil)
+	mockRepo.On("SumPoints", "").Return(int64(0), errors.New("database error"))
+	mockRepo.On("CountJoinedSince", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("GetTopByPoints", mock.Anything).Return([]domain.User{}, nil)
+
+	// Act
+	dashboard, err := useCase.GetDashboard()
+
+	// Assert
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, dashboard)
+}
+
+func TestUserUseCase_GetUserByMembershipID_Normalizes(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	expected := &domain.User{ID: 1, MembershipID: "LBK123456"}
+	mockRepo.On("GetByMembershipID", "LBK123456").Return(expected, nil)
+
+	// Act
+	user, err := useCase.GetUserByMembershipID(" lbk123456 ")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, user)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_GetUsersByMembershipIDs(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("GetByMembershipIDs", []string{"LBK000002", "LBK000001", "LBK999999"}).Return([]domain.User{
+		{ID: 1, MembershipID: "LBK000001"},
+		{ID: 2, MembershipID: "LBK000002"},
+	}, nil)
+
+	// Act
+	users, notFound, err := useCase.GetUsersByMembershipIDs([]string{" lbk000002", "LBK000001", "LBK999999", "LBK000001"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.User{{ID: 2, MembershipID: "LBK000002"}, {ID: 1, MembershipID: "LBK000001"}}, users)
+	assert.Equal(t, []string{"LBK999999"}, notFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_GetUsersByMembershipIDs_InvalidBatch(t *testing.T) {
+	tooMany := make([]string, maxMembershipIDBatch+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("LBK%06d", i)
+	}
+	tests := []struct {
+		name string
+		ids  []string
+	}{
+		{"empty", nil},
+		{"too many", tooMany},
+		{"malformed", []string{"LBK000001", "card-42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+			// Act
+			_, _, err := useCase.GetUsersByMembershipIDs(tt.ids)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+			mockRepo.AssertNotCalled(t, "GetByMembershipIDs", mock.Anything)
+		})
+	}
+}
+
+func TestUserUseCase_ReissueInvalidMembershipIDs(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	useCase.(*userUseCase).generateID = func() string { return "LBK000777" }
+
+	mockRepo.On("GetAll").Return([]domain.User{
+		{ID: 1, MembershipID: "LBK123456"},
+		{ID: 2, MembershipID: "legacy-2"},
+	}, nil)
+	mockRepo.On("MembershipIDInUse", "LBK000777").Return(false, nil)
+	mockRepo.On("UpdateMembershipID", uint(2), "LBK000777").Return(nil)
+
+	// Act
+	changes, err := useCase.ReissueInvalidMembershipIDs()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.MembershipIDChange{{UserID: 2, OldID: "legacy-2", NewID: "LBK000777"}}, changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ImportPoints_RateLimit(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{PointsRateLimit: 1000, PointsRateWindow: time.Hour})
+	guard := domain.PointsGuard{RateLimit: 1000, RateWindow: time.Hour}
+	mockRepo.On("GetByMembershipID", "LBK000001").Return(&domain.User{ID: 1, MembershipID: "LBK000001", Points: 5000}, nil)
+	mockRepo.On("SumPointsChange", uint(1), mock.AnythingOfType("time.Time")).Return(int64(300), nil).Once()
+	mockRepo.On("AdjustPoints", []domain.PointsAdjustment{{UserID: 1, Delta: 500, Reason: domain.PointsReasonImport}}, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, guard).Return(nil)
+
+	// Act
+	results, err := useCase.ImportPoints(strings.NewReader("membership_id,delta\nLBK000001,500\nLBK000001,-300\n"), "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ImportRowApplied, results[0].Status)
+	assert.Equal(t, domain.ImportRowError, results[1].Status)
+	assert.Contains(t, results[1].Error, domain.ErrPointsRateExceeded.Error())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ImportPoints_RunningBalance(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+	mockRepo.On("GetByMembershipID", "LBK000001").Return(&domain.User{ID: 1, MembershipID: "LBK000001", Points: 100}, nil)
+	mockRepo.On("AdjustPoints", []domain.PointsAdjustment{{UserID: 1, Delta: -80, Reason: domain.PointsReasonImport}}, domain.TierPolicy{Thresholds: domain.DefaultTierThresholds}, domain.PointsGuard{}).Return(nil)
+
+	// Act
+	results, err := useCase.ImportPoints(strings.NewReader("membership_id,delta\nLBK000001,-80\nLBK000001,-30\n"), "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ImportRowResult{
+		{Row: 2, Status: domain.ImportRowApplied, UserID: 1},
+		{Row: 3, Status: domain.ImportRowError, UserID: 1, Error: "points must not be negative"},
+	}, results)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ImportPoints_InvalidMode(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	// Act
+	_, err := useCase.ImportPoints(strings.NewReader("membership_id,delta\n"), "some")
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidImportMode)
+}
+
+func TestUserUseCase_ImportUsersFromURL_TooLarge(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "first_name,last_name,email\n"+strings.Repeat("x", 100))
+	}))
+	defer server.Close()
+
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{
+		ImportAllowedHosts: []string{"127.0.0.1"},
+		ImportMaxBytes:     64,
+	})
+
+	// Act
+	_, err := useCase.ImportUsersFromURL(context.Background(), server.URL)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrImportTooLarge)
+}
+
+func TestUserUseCase_ImportUsersFromURL_RedirectToDisallowedHost(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{ImportAllowedHosts: []string{"127.0.0.1"}})
+
+	// Act
+	_, err := useCase.ImportUsersFromURL(context.Background(), server.URL)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrImportURLNotAllowed)
+}
+
+func TestUserUseCase_FindDuplicateCandidates(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{})
+
+	users := []domain.User{
+		{ID: 1, FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678"},
+		{ID: 2, FirstName: "Jon", LastName: "doe ", Email: "jon.doe@example.com", Phone: "0812345678"},
+		{ID: 3, FirstName: "John", LastName: "Smith", Email: "smith@example.com", Phone: "089-765-4321"},
+	}
+	mockRepo.On("GetAll").Return(users, nil)
+
+	// Act
+	candidates, err := useCase.FindDuplicateCandidates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.DuplicateCandidate{
+		{UserID: 1, OtherUserID: 2, Score: 80, Matched: []string{"last_name", "phone"}},
+	}, candidates)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_FindDuplicateCandidates_ConfiguredRules(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{
+		DuplicateRules:    map[string]int{"first_name": 60},
+		DuplicateMinScore: 50,
+	})
+
+	users := []domain.User{
+		{ID: 1, FirstName: "John", LastName: "Doe", Phone: "081-234-5678"},
+		{ID: 2, FirstName: "Jon", LastName: "Doe", Phone: "0812345678"},
+		{ID: 3, FirstName: "john", LastName: "Smith"},
+	}
+	mockRepo.On("GetAll").Return(users, nil)
+
+	// Act
+	candidates, err := useCase.FindDuplicateCandidates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.DuplicateCandidate{
+		{UserID: 1, OtherUserID: 3, Score: 60, Matched: []string{"first_name"}},
+	}, candidates)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_ReconcileTiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		corrected bool
+	}{
+		{"report only", config.ReconcileModeReport, false},
+		{"correct", config.ReconcileModeCorrect, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(mocks.MockUserRepository)
+			useCase := NewUserUseCase(mockRepo, &config.Config{
+				TierReconcileMode: tt.mode,
+				MembershipTypes:   []string{"Bronze", "Silver", "Gold", "Platinum"},
+			})
+
+			users := []domain.User{
+				{ID: 1, MembershipType: "Gold", Points: 15000},
+				{ID: 2, MembershipType: "Gold", Points: 200},
+				{ID: 3, MembershipType: "Platinum", Points: 200},
+			}
+			mockRepo.On("GetAll").Return(users, nil)
+			if tt.corrected {
+				mockRepo.On("SetMembershipType", uint(2), "Bronze", domain.TierTriggerReconcile).Return(nil)
+			}
+
+			// Act
+			mismatches, err := useCase.ReconcileTiers()
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.TierMismatch{
+				{UserID: 2, Points: 200, Stored: "Gold", Expected: "Bronze", Corrected: tt.corrected},
+			}, mismatches)
+			if !tt.corrected {
+				mockRepo.AssertNotCalled(t, "SetMembershipType", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserUseCase_SimulateTiers_ConfiguredTiers(t *testing.T) {
+	// Arrange
+	mockRepo := new(mocks.MockUserRepository)
+	useCase := NewUserUseCase(mockRepo, &config.Config{MembershipTypes: []string{"Bronze", "Silver", "Gold", "Platinum"}})
+
+	thresholds := domain.TierThresholds{SilverMinPoints: 100, GoldMinPoints: 200}
+	mockRepo.On("CountTierMoves", thresholds).Return([]domain.TierMove{
+		{From: "Bronze", To: "Silver", Count: 2},
+		{From: "Platinum", To: "Bronze", Count: 1},
+	}, nil)
+
+	// Act
+	simulations, err := useCase.SimulateTiers(thresholds)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TierSimulation{
+		{MembershipType: "Bronze", CurrentCount: 2, SimulatedCount: 0, MovingOut: 2},
+		{MembershipType: "Silver", CurrentCount: 0, SimulatedCount: 2, MovingIn: 2},
+		{MembershipType: "Gold"},
+		{MembershipType: "Platinum", CurrentCount: 1, SimulatedCount: 1},
+	}, simulations)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// validateStruct checks a request struct against its validate tags and returns
+// a *domain.ValidationError listing every failing field. Fields are reported by
+// their JSON name. Supported rules are required, omitempty, email, phone and nonnegative;
+// a tag using any other rule is reported as an error rather than a failing field.
+func validateStruct(v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	typ := value.Type()
+
+	var fields []domain.FieldError
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = typ.Field(i).Name
+		}
+
+		message, err := checkRules(reflect.Indirect(value.Field(i)), strings.Split(tag, ","))
+		if err != nil {
+			return fmt.Errorf("validate %s: %w", typ.Field(i).Name, err)
+		}
+		if message != "" {
+			fields = append(fields, domain.FieldError{Field: name, Message: message})
+		}
+	}
+
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// checkRules returns why a field value breaks its rules, or "" when it passes.
+// It fails on a rule it does not support or that does not suit the field's type.
+func checkRules(field reflect.Value, rules []string) (string, error) {
+	empty := !field.IsValid() || field.IsZero() ||
+		(field.Kind() == reflect.String && strings.TrimSpace(field.String()) == "")
+
+	for _, rule := range rules {
+		switch rule {
+		case "required":
+			if empty {
+				return "is required", nil
+			}
+		case "omitempty":
+			if empty {
+				return "", nil
+			}
+		case "email":
+			if !validEmail(field.String()) {
+				return "must be a valid email address", nil
+			}
+		case "phone":
+			if message := checkPhone(field.String()); message != "" {
+				return message, nil
+			}
+		case "nonnegative":
+			if !field.IsValid() {
+				continue
+			}
+			if !field.CanInt() {
+				return "", fmt.Errorf("rule nonnegative needs an integer, got %s", field.Kind())
+			}
+			if field.Int() < 0 {
+				return "must not be negative", nil
+			}
+		default:
+			return "", fmt.Errorf("unsupported rule %q", rule)
+		}
+	}
+	return "", nil
+}
+
+// Digit counts accepted for a phone number: Thai landlines have 9 digits and
+// E.164 numbers at most 15
+const (
+	minPhoneDigits = 9
+	maxPhoneDigits = 15
+)
+
+// checkPhone returns why a phone number is not usable, or "" when it is. Digits
+// may be grouped with spaces, dashes, dots, parentheses and a leading plus.
+func checkPhone(phone string) string {
+	for _, r := range strings.TrimSpace(phone) {
+		if !unicode.IsDigit(r) && !strings.ContainsRune(" -.()+", r) {
+			return "must contain only digits and separators"
+		}
+	}
+	if digits := len(normalizePhone(phone)); digits < minPhoneDigits || digits > maxPhoneDigits {
+		return fmt.Sprintf("must have %d to %d digits", minPhoneDigits, maxPhoneDigits)
+	}
+	return ""
+}
+
+// validEmail reports whether s is a bare address such as john@example.com
+func validEmail(s string) bool {
+	address, err := mail.ParseAddress(s)
+	return err == nil && address.Address == s
+}
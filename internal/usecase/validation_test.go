@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+func TestValidEmail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"john@example.com", true},
+		{"john.doe+loyalty@example.co.th", true},
+		{"notanemail", false},
+		{"john@", false},
+		{"@example.com", false},
+		{"John <john@example.com>", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validEmail(tt.input))
+		})
+	}
+}
+
+func TestValidateStruct(t *testing.T) {
+	negative := -5
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected []domain.FieldError
+	}{
+		{
+			name:  "valid create request",
+			input: domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"},
+		},
+		{
+			name:  "blank names are required",
+			input: domain.CreateUserRequest{FirstName: "  ", Email: "john@example.com"},
+			expected: []domain.FieldError{
+				{Field: "first_name", Message: "is required"},
+				{Field: "last_name", Message: "is required"},
+			},
+		},
+		{
+			name:  "omitted update email is skipped",
+			input: domain.UpdateUserRequest{FirstName: "Jane"},
+		},
+		{
+			name:     "update email must be an address",
+			input:    domain.UpdateUserRequest{Email: "notanemail"},
+			expected: []domain.FieldError{{Field: "email", Message: "must be a valid email address"}},
+		},
+		{
+			name:     "create points must not be negative",
+			input:    domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", Points: -1},
+			expected: []domain.FieldError{{Field: "points", Message: "must not be negative"}},
+		},
+		{
+			name:     "update points must not be negative",
+			input:    domain.UpdateUserRequest{Points: &negative},
+			expected: []domain.FieldError{{Field: "points", Message: "must not be negative"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStruct(tt.input)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *domain.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expected, validationErr.Fields)
+		})
+	}
+}
+
+func TestValidateStruct_UnsupportedRule(t *testing.T) {
+	// Arrange
+	input := struct {
+		Name string `json:"name" validate:"required,uppercase"`
+	}{Name: "john"}
+
+	// Act
+	err := validateStruct(input)
+
+	// Assert
+	assert.EqualError(t, err, `validate Name: unsupported rule "uppercase"`)
+	var validationErr *domain.ValidationError
+	assert.False(t, errors.As(err, &validationErr))
+}
@@ -1,52 +1,164 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
 	"kbtg.tech/ai-backend-workshop/internal/handler"
+	"kbtg.tech/ai-backend-workshop/internal/job"
 	"kbtg.tech/ai-backend-workshop/internal/repository"
 	"kbtg.tech/ai-backend-workshop/internal/usecase"
 	"kbtg.tech/ai-backend-workshop/pkg/database"
+	"kbtg.tech/ai-backend-workshop/pkg/events"
+	"kbtg.tech/ai-backend-workshop/pkg/logging"
+	"kbtg.tech/ai-backend-workshop/pkg/notify"
+	"kbtg.tech/ai-backend-workshop/pkg/webhook"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.NewConfig()
 
+	// Route every log record, including those from the log package, through the configured logger
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	if err := domain.CheckMembershipTypes(cfg.MembershipTypes); err != nil {
+		log.Fatalf("Invalid membership types: %v", err)
+	}
+	if err := database.CheckMembershipIDNamespace(cfg.MembershipIDNamespace); err != nil {
+		log.Fatalf("Invalid membership ID namespace: %v", err)
+	}
+
 	// Initialize database
 	db, err := database.NewDatabase(cfg.DBPath)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Route reads to the replica when one is configured
+	if cfg.DBReplicaDSN != "" {
+		if err := db.ConnectReplica(cfg.DBReplicaDSN); err != nil {
+			log.Fatalf("Failed to initialize read replica: %v", err)
+		}
+	}
+
+	// Tune the connection pool of the primary and any replica
+	pool := database.PoolSettings{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+	}
+	if err := db.ConfigurePool(pool); err != nil {
+		log.Fatalf("Failed to configure database pool: %v", err)
+	}
+	if cfg.DebugMode {
+		log.Printf("Database pool: max_open_conns=%d max_idle_conns=%d conn_max_lifetime=%s", pool.MaxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+	}
+
+	// Encrypt emails and phone numbers at rest, including rows stored in plaintext
+	if cfg.PIIEncryptionKey != "" {
+		cipher, err := database.NewPIICipher(cfg.PIIEncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize PII encryption: %v", err)
+		}
+		db.EnablePIIEncryption(cipher)
+		migrated, err := db.EncryptPlaintextPII()
+		if err != nil {
+			log.Fatalf("Failed to encrypt stored PII: %v", err)
+		}
+		if migrated > 0 {
+			log.Printf("Encrypted PII of %d existing users", migrated)
+		}
+	}
+
 	// Seed database
-	if err := db.SeedData(); err != nil {
+	if err := db.SeedData(cfg.AppEnv); err != nil {
 		log.Fatalf("Failed to seed database: %v", err)
 	}
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
+	pointsTxnRepo := repository.NewPointsTransactionRepository(db)
+
+	// User lifecycle events are published in process for subscribers to react to
+	bus := events.NewBus()
+	userOpts := []usecase.Option{usecase.WithEventPublisher(bus)}
+
+	// Send new users a welcome email when a mail server is configured
+	if cfg.SMTPHost != "" {
+		userOpts = append(userOpts, usecase.WithNotifier(notify.NewSMTP(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}, nil)))
+	}
 
 	// Initialize use cases
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, cfg, userOpts...)
+	pointsUseCase := usecase.NewPointsUseCase(userRepo, pointsTxnRepo)
+
+	// Cancelled on SIGINT/SIGTERM to stop background jobs and the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// POST user events to the configured webhooks without holding up responses
+	if len(cfg.WebhookURLs) > 0 {
+		dispatcher, err := webhook.NewDispatcher(webhook.Config{
+			URLs:        cfg.WebhookURLs,
+			Secret:      cfg.WebhookSecret,
+			MaxAttempts: cfg.WebhookMaxAttempts,
+			Backoff:     cfg.WebhookBackoff,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure webhooks: %v", err)
+		}
+		bus.Subscribe(dispatcher.Enqueue)
+		go dispatcher.Run(ctx)
+	}
+
+	// Check stored tiers against points in the background
+	if cfg.TierReconcileInterval > 0 {
+		go job.RunTierReconciliation(ctx, userUseCase, cfg.TierReconcileInterval)
+	}
 
 	// Initialize handlers
 	userHandler := handler.NewUserHandler(userUseCase)
+	adminHandler := handler.NewAdminHandler(userUseCase, cfg)
+	pointsHandler := handler.NewPointsHandler(pointsUseCase)
+	healthHandler := handler.NewHealthHandler(cfg, db.Ping, database.StatFilesystem)
+
+	auth, err := handler.NewAuth(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
-		AppName: cfg.AppName,
+		AppName:      cfg.AppName,
+		JSONEncoder:  handler.NewJSONEncoder(cfg.JSONCase),
+		ErrorHandler: handler.ErrorHandler,
 	})
 
 	// Add middleware
-	app.Use(logger.New())
-	app.Use(recover.New())
+	app.Use(handler.NewRequestLogger())
+	app.Use(handler.NewRecover(cfg))
+	app.Use(handler.NewCompression(cfg))
+	app.Use(handler.NewDebugTiming(cfg))
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
@@ -54,24 +166,44 @@ func main() {
 	}))
 
 	// Setup routes
-	setupRoutes(app, userHandler)
+	setupRoutes(app, cfg, auth, userHandler, adminHandler, pointsHandler, healthHandler)
 
 	// Start server
 	log.Printf("Server starting on port %s", cfg.Port)
-	log.Fatal(app.Listen(":" + cfg.Port))
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(":" + cfg.Port)
+	}()
+
+	select {
+	case err := <-listenErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// Stop accepting connections and let in-flight requests finish
+	log.Printf("Shutting down (timeout %s)", cfg.ShutdownTimeout)
+	if err := app.ShutdownWithTimeout(cfg.ShutdownTimeout); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	log.Println("Server stopped")
 }
 
-func setupRoutes(app *fiber.App, userHandler *handler.UserHandler) {
+func setupRoutes(app *fiber.App, cfg *config.Config, auth fiber.Handler, userHandler *handler.UserHandler, adminHandler *handler.AdminHandler, pointsHandler *handler.PointsHandler, healthHandler *handler.HealthHandler) {
 	// API v1
 	api := app.Group("/api/v1")
 
-	// Health check endpoint
-	api.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"message": "KBTG AI Backend Workshop is running!",
-		})
-	})
+	// Health, liveness and readiness endpoints
+	healthHandler.RegisterRoutes(api)
+
+	// Everything registered after this point is rate limited per client IP and
+	// requires authentication; the health endpoints above stay open for
+	// orchestrator probes
+	api.Use(handler.NewRateLimiter(cfg))
+	api.Use(auth)
 
 	// Hello World endpoint
 	api.Get("/hello", func(c *fiber.Ctx) error {
@@ -85,12 +217,13 @@ func setupRoutes(app *fiber.App, userHandler *handler.UserHandler) {
 	})
 
 	// User routes
-	users := api.Group("/users")
-	users.Get("/", userHandler.GetUsers)
-	users.Get("/:id", userHandler.GetUser)
-	users.Post("/", userHandler.CreateUser)
-	users.Put("/:id", userHandler.UpdateUser)
-	users.Delete("/:id", userHandler.DeleteUser)
+	userHandler.RegisterRoutes(api)
+
+	// Points ledger routes
+	pointsHandler.RegisterRoutes(api)
+
+	// Admin routes, guarded by their own keys and only mounted when some are configured
+	adminHandler.RegisterRoutes(api)
 
 	// Static files
 	app.Static("/", "./public")
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors returned (wrapped in *APIError) for non-2xx responses
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError describes a non-2xx response from the API
+type APIError struct {
+	StatusCode int
+	// Code is the API's machine-readable error code, such as USER_NOT_FOUND
+	Code    string
+	Message string
+	// Fields lists the request fields that failed validation, if any
+	Fields []FieldError
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Is maps the status code to one of the sentinel errors so callers can use errors.Is
+func (e *APIError) Is(target error) bool {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return target == ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case e.StatusCode == http.StatusNotFound:
+		return target == ErrNotFound
+	case e.StatusCode >= http.StatusInternalServerError:
+		return target == ErrServer
+	}
+	return false
+}
+
+// Client is a typed HTTP client for the users API
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	apiKey      string
+	bearerToken string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient overrides the underlying HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey sends the given key in the X-API-Key header
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken sends the given token in the Authorization header
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+// New creates a new client for the API served at baseURL (e.g. http://localhost:3000/api/v1)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ListOptions holds the query filters sent with ListUsers
+type ListOptions struct {
+	Filters url.Values
+}
+
+// ListUsers retrieves users, passing any filters through as query parameters
+func (c *Client) ListUsers(ctx context.Context, opts *ListOptions) ([]User, error) {
+	path := "/users"
+	if opts != nil && len(opts.Filters) > 0 {
+		path += "?" + opts.Filters.Encode()
+	}
+
+	var users []User
+	if err := c.do(ctx, http.MethodGet, path, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUser retrieves a user by ID
+func (c *Client) GetUser(ctx context.Context, id uint) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, userPath(id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateUser creates a new user
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodPost, "/users", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser updates an existing user
+func (c *Client) UpdateUser(ctx context.Context, id uint, req UpdateUserRequest) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodPut, userPath(id), req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser deletes a user by ID
+func (c *Client) DeleteUser(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, userPath(id), nil, nil)
+}
+
+// userPath builds the path of a single user resource
+func userPath(id uint) string {
+	return "/users/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// do sends the request and decodes the "data" field of the response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
+
+// decodeError builds an APIError from an error response
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	var payload struct {
+		Error struct {
+			Code    string       `json:"code"`
+			Message string       `json:"message"`
+			Details []FieldError `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err == nil {
+		apiErr.Code = payload.Error.Code
+		apiErr.Message = payload.Error.Message
+		apiErr.Fields = payload.Error.Details
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"kbtg.tech/ai-backend-workshop/internal/config"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+	"kbtg.tech/ai-backend-workshop/internal/handler"
+	"kbtg.tech/ai-backend-workshop/internal/repository"
+	"kbtg.tech/ai-backend-workshop/internal/usecase"
+	"kbtg.tech/ai-backend-workshop/pkg/database"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	db     *database.DB
+	server *httptest.Server
+	client *Client
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	// Create in-memory SQLite database for testing
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	suite.Require().NoError(err)
+
+	// Each in-memory connection is a separate database, so keep the pool to one
+	sqlDB, err := gormDB.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
+	suite.db = &database.DB{DB: gormDB}
+	err = suite.db.AutoMigrate(&domain.User{}, &domain.PointsTransaction{}, &domain.TierChange{}, &domain.RevokedMembershipID{}, &domain.IdempotencyKey{})
+	suite.Require().NoError(err)
+
+	// Serve the real handlers over HTTP
+	userRepo := repository.NewUserRepository(suite.db)
+	userUseCase := usecase.NewUserUseCase(userRepo, &config.Config{})
+	userHandler := handler.NewUserHandler(userUseCase)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	userHandler.RegisterRoutes(app.Group("/api/v1"))
+
+	suite.server = httptest.NewServer(adaptor.FiberApp(app))
+	suite.client = New(suite.server.URL + "/api/v1")
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *ClientTestSuite) TestCreateAndGetUser() {
+	ctx := context.Background()
+
+	// Act
+	created, err := suite.client.CreateUser(ctx, CreateUserRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	})
+	suite.Require().NoError(err)
+
+	fetched, err := suite.client.GetUser(ctx, created.ID)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal("John", fetched.FirstName)
+	suite.Equal("Bronze", fetched.MembershipType)
+	suite.NotEmpty(fetched.MembershipID)
+	suite.Equal("active", fetched.Status)
+}
+
+func (suite *ClientTestSuite) TestListUsers() {
+	ctx := context.Background()
+	suite.Require().NoError(suite.db.Create(&domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001"}).Error)
+	suite.Require().NoError(suite.db.Create(&domain.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK000002"}).Error)
+
+	// Act
+	users, err := suite.client.ListUsers(ctx, &ListOptions{Filters: url.Values{"membership_type": {"Bronze"}}})
+
+	// Assert
+	suite.NoError(err)
+	suite.Len(users, 2)
+}
+
+func (suite *ClientTestSuite) TestUpdateAndDeleteUser() {
+	ctx := context.Background()
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	// Act
+	updated, err := suite.client.UpdateUser(ctx, user.ID, UpdateUserRequest{FirstName: "Jane"})
+	suite.Require().NoError(err)
+	err = suite.client.DeleteUser(ctx, user.ID)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal("Jane", updated.FirstName)
+	_, err = suite.client.GetUser(ctx, user.ID)
+	suite.ErrorIs(err, ErrNotFound)
+}
+
+func (suite *ClientTestSuite) TestTypedErrors() {
+	ctx := context.Background()
+
+	// Act
+	_, notFoundErr := suite.client.GetUser(ctx, 999)
+	_, badRequestErr := suite.client.CreateUser(ctx, CreateUserRequest{FirstName: "John"})
+
+	// Assert
+	suite.ErrorIs(notFoundErr, ErrNotFound)
+	suite.ErrorIs(badRequestErr, ErrBadRequest)
+
+	var apiErr *APIError
+	suite.Require().True(errors.As(badRequestErr, &apiErr))
+	suite.Equal(http.StatusBadRequest, apiErr.StatusCode)
+	suite.Equal("VALIDATION_FAILED", apiErr.Code)
+	suite.Equal("validation failed", apiErr.Message)
+	suite.Equal([]FieldError{
+		{Field: "last_name", Message: "is required"},
+		{Field: "email", Message: "is required"},
+	}, apiErr.Fields)
+}
+
+func (suite *ClientTestSuite) TestAuthHeaders() {
+	var apiKey, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-API-Key")
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[],"count":0}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithAPIKey("secret"), WithBearerToken("token"))
+
+	// Act
+	_, err := c.ListUsers(context.Background(), nil)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal("secret", apiKey)
+	suite.Equal("Bearer token", authorization)
+}
+
+func (suite *ClientTestSuite) TestContextCancellation() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := suite.client.GetUser(ctx, 1)
+
+	// Assert
+	suite.ErrorIs(err, context.Canceled)
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,48 @@
+package client
+
+import "time"
+
+// User is a user as returned by the API
+type User struct {
+	ID             uint       `json:"id"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Email          string     `json:"email"`
+	Phone          string     `json:"phone"`
+	MembershipType string     `json:"membership_type"`
+	MembershipID   string     `json:"membership_id"`
+	JoinDate       time.Time  `json:"join_date"`
+	Points         int        `json:"points"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+}
+
+// CreateUserRequest holds the fields sent to create a user
+type CreateUserRequest struct {
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone,omitempty"`
+	MembershipType string `json:"membership_type,omitempty"`
+	Points         int    `json:"points,omitempty"`
+}
+
+// UpdateUserRequest holds the fields sent to update a user. Phone and Points are
+// pointers so that an omitted field is left unchanged while "" or 0 clears it.
+type UpdateUserRequest struct {
+	FirstName      string  `json:"first_name,omitempty"`
+	LastName       string  `json:"last_name,omitempty"`
+	Email          string  `json:"email,omitempty"`
+	Phone          *string `json:"phone,omitempty"`
+	MembershipType string  `json:"membership_type,omitempty"`
+	Points         *int    `json:"points,omitempty"`
+	AllowDowngrade bool    `json:"allow_downgrade,omitempty"`
+}
+
+// FieldError describes one request field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
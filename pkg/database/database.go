@@ -1,8 +1,11 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
+	"regexp"
 	"time"
 
 	"gorm.io/driver/sqlite"
@@ -13,6 +16,12 @@ import (
 // DB holds the database connection
 type DB struct {
 	*gorm.DB
+
+	// Replica is an optional read-only connection used for queries
+	Replica *gorm.DB
+
+	// PII encrypts emails and phone numbers at rest when set; see EnablePIIEncryption
+	PII *PIICipher
 }
 
 // NewDatabase creates a new database connection
@@ -22,17 +31,129 @@ func NewDatabase(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Auto-migrate the User model
-	err = db.AutoMigrate(&domain.User{})
+	// Auto-migrate the models
+	err = db.AutoMigrate(&domain.User{}, &domain.PointsTransaction{}, &domain.TierChange{}, &domain.RevokedMembershipID{}, &domain.IdempotencyKey{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
+}
+
+// ConnectReplica opens the read replica at dsn and routes read-only queries to it
+func (db *DB) ConnectReplica(dsn string) error {
+	replica, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+
+	if db.PII != nil {
+		replica = withPII(replica, db.PII)
+	}
+	db.Replica = replica
+	return nil
+}
+
+// PoolSettings tunes the connection pool; zero leaves a setting at the database/sql default
+type PoolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Validate rejects negative settings and more idle than open connections
+func (p PoolSettings) Validate() error {
+	if p.MaxOpenConns < 0 || p.MaxIdleConns < 0 || p.ConnMaxLifetime < 0 {
+		return errors.New("invalid pool settings: values must not be negative")
+	}
+	if p.MaxOpenConns > 0 && p.MaxIdleConns > p.MaxOpenConns {
+		return fmt.Errorf("invalid pool settings: max idle connections (%d) exceed max open connections (%d)", p.MaxIdleConns, p.MaxOpenConns)
+	}
+	return nil
+}
+
+// ConfigurePool applies the pool settings to the primary connection and any read replica
+func (db *DB) ConfigurePool(p PoolSettings) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	for _, conn := range db.connections() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return err
+		}
+		if p.MaxOpenConns > 0 {
+			sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+		}
+		if p.MaxIdleConns > 0 {
+			sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+		}
+		if p.ConnMaxLifetime > 0 {
+			sqlDB.SetConnMaxLifetime(p.ConnMaxLifetime)
+		}
+	}
+	return nil
+}
+
+// connections returns the primary connection followed by the read replica, if any
+func (db *DB) connections() []*gorm.DB {
+	conns := []*gorm.DB{db.DB}
+	if db.Replica != nil {
+		conns = append(conns, db.Replica)
+	}
+	return conns
 }
 
-// SeedData seeds the database with initial data
-func (db *DB) SeedData() error {
+// Close closes the primary connection and any read replica
+func (db *DB) Close() error {
+	var errs []error
+	for _, conn := range db.connections() {
+		sqlDB, err := conn.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Ping checks that the primary database is reachable
+func (db *DB) Ping() error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Ping()
+}
+
+// InTransaction runs fn against a DB bound to a single transaction, committing
+// when fn returns nil and rolling back otherwise. Reads inside fn go to the
+// transaction rather than the replica so they see its writes.
+func (db *DB) InTransaction(fn func(tx *DB) error) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&DB{DB: tx, PII: db.PII})
+	})
+}
+
+// Reader returns the connection for read-only queries, falling back to the primary
+func (db *DB) Reader() *gorm.DB {
+	if db.Replica != nil {
+		return db.Replica
+	}
+	return db.DB
+}
+
+// seedEnvs lists the environments that get the sample users
+var seedEnvs = map[string]bool{"dev": true, "test": true}
+
+// SeedData seeds an empty database with sample users, only in the dev and test environments
+func (db *DB) SeedData(appEnv string) error {
+	if !seedEnvs[appEnv] {
+		log.Printf("Skipping seed data in %q environment", appEnv)
+		return nil
+	}
+
 	// Check if users already exist
 	var count int64
 	db.Model(&domain.User{}).Count(&count)
@@ -64,16 +185,54 @@ func (db *DB) SeedData() error {
 	}
 
 	for _, user := range seedUsers {
+		user.EmailHash = db.HashEmail(user.Email)
 		if err := db.Create(&user).Error; err != nil {
 			return fmt.Errorf("failed to seed user: %w", err)
 		}
+		txn := domain.PointsTransaction{UserID: user.ID, Delta: user.Points, Balance: user.Points, Reason: domain.PointsReasonInitial}
+		if err := db.Create(&txn).Error; err != nil {
+			return fmt.Errorf("failed to seed points ledger: %w", err)
+		}
 	}
 
 	log.Println("Database seeded with initial users")
 	return nil
 }
 
-// GenerateMembershipID generates a random membership ID
-func GenerateMembershipID() string {
-	return fmt.Sprintf("LBK%06d", time.Now().UnixNano()%999999)
+// membershipIDPattern matches legacy IDs such as LBK000123
+var membershipIDPattern = regexp.MustCompile(`^LBK\d{6}$`)
+
+// namespacedIDPattern matches IDs carrying an environment code, such as LBK-PROD-000123
+var namespacedIDPattern = regexp.MustCompile(`^LBK-([A-Z0-9]+)-\d{6}$`)
+
+// namespacePattern matches the environment codes membership IDs may embed
+var namespacePattern = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// CheckMembershipIDNamespace rejects an environment namespace that generated IDs
+// could not be validated against. An empty namespace keeps the legacy format.
+func CheckMembershipIDNamespace(namespace string) error {
+	if namespace != "" && !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("membership ID namespace %q must contain only A-Z and 0-9", namespace)
+	}
+	return nil
+}
+
+// ValidMembershipID reports whether id is a well-formed membership ID for the given
+// environment namespace. Legacy IDs stay valid; namespaced IDs must carry this namespace.
+func ValidMembershipID(id, namespace string) bool {
+	if membershipIDPattern.MatchString(id) {
+		return true
+	}
+	match := namespacedIDPattern.FindStringSubmatch(id)
+	return namespace != "" && match != nil && match[1] == namespace
+}
+
+// GenerateMembershipID generates a random membership ID, embedding the environment
+// namespace when one is set. Callers must still check the ID is unused.
+func GenerateMembershipID(namespace string) string {
+	number := rand.IntN(1000000)
+	if namespace == "" {
+		return fmt.Sprintf("LBK%06d", number)
+	}
+	return fmt.Sprintf("LBK-%s-%06d", namespace, number)
 }
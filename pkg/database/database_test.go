@@ -0,0 +1,169 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+func TestGenerateMembershipID(t *testing.T) {
+	// Act
+	legacy := GenerateMembershipID("")
+	namespaced := GenerateMembershipID("PROD")
+
+	// Assert
+	assert.Regexp(t, `^LBK\d{6}$`, legacy)
+	assert.Regexp(t, `^LBK-PROD-\d{6}$`, namespaced)
+	assert.True(t, ValidMembershipID(legacy, ""))
+	assert.True(t, ValidMembershipID(namespaced, "PROD"))
+}
+
+func TestValidMembershipID(t *testing.T) {
+	tests := []struct {
+		id        string
+		namespace string
+		valid     bool
+	}{
+		{"LBK000123", "", true},
+		{"LBK000123", "PROD", true},
+		{"LBK-PROD-000123", "PROD", true},
+		{"LBK-PROD-000123", "", false},
+		{"LBK-STG-000123", "PROD", false},
+		{"LBK-PROD-123", "PROD", false},
+		{"LBK12345", "", false},
+		{"XYZ000123", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id+"/"+tt.namespace, func(t *testing.T) {
+			assert.Equal(t, tt.valid, ValidMembershipID(tt.id, tt.namespace))
+		})
+	}
+}
+
+func TestCheckMembershipIDNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		valid     bool
+	}{
+		{"", true},
+		{"PROD", true},
+		{"STG2", true},
+		{"PR-OD", false},
+		{"PROD ", false},
+		{"prod", false},
+		{"ทดสอบ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			// Act
+			err := CheckMembershipIDNamespace(tt.namespace)
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSeedData_ByEnvironment(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected int64
+	}{
+		{"dev", 2},
+		{"test", 2},
+		{"prod", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			// Arrange
+			db, err := NewDatabase("file:" + tt.env + "?mode=memory&cache=shared")
+			assert.NoError(t, err)
+
+			// Act
+			err = db.SeedData(tt.env)
+
+			// Assert
+			assert.NoError(t, err)
+			var count int64
+			db.Model(&domain.User{}).Count(&count)
+			assert.Equal(t, tt.expected, count)
+		})
+	}
+}
+
+func TestSeedData_SkipsNonEmptyTable(t *testing.T) {
+	// Arrange
+	db, err := NewDatabase("file:nonempty?mode=memory&cache=shared")
+	assert.NoError(t, err)
+	assert.NoError(t, db.Create(&domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001"}).Error)
+
+	// Act
+	err = db.SeedData("dev")
+
+	// Assert
+	assert.NoError(t, err)
+	var count int64
+	db.Model(&domain.User{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestClose(t *testing.T) {
+	// Arrange
+	db, err := NewDatabase("file:close?mode=memory&cache=shared")
+	assert.NoError(t, err)
+	assert.NoError(t, db.ConnectReplica("file:close?mode=memory&cache=shared"))
+
+	// Act
+	err = db.Close()
+
+	// Assert
+	assert.NoError(t, err)
+	primary, _ := db.DB.DB()
+	assert.Error(t, primary.Ping())
+	replica, _ := db.Replica.DB()
+	assert.Error(t, replica.Ping())
+}
+
+func TestConfigurePool(t *testing.T) {
+	// Arrange
+	db, err := NewDatabase("file:pool?mode=memory&cache=shared")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// Act
+	err = db.ConfigurePool(PoolSettings{MaxOpenConns: 4, MaxIdleConns: 2, ConnMaxLifetime: time.Minute})
+
+	// Assert
+	assert.NoError(t, err)
+	sqlDB, _ := db.DB.DB()
+	assert.Equal(t, 4, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestPoolSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    PoolSettings
+		wantErr bool
+	}{
+		{"defaults", PoolSettings{}, false},
+		{"idle within open", PoolSettings{MaxOpenConns: 10, MaxIdleConns: 5}, false},
+		{"idle without open limit", PoolSettings{MaxIdleConns: 5}, false},
+		{"idle above open", PoolSettings{MaxOpenConns: 2, MaxIdleConns: 5}, true},
+		{"negative", PoolSettings{MaxOpenConns: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.pool.Validate() != nil)
+		})
+	}
+}
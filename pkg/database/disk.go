@@ -0,0 +1,55 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLowDiskSpace is returned when the database's filesystem is below its free space threshold
+var ErrLowDiskSpace = errors.New("low disk space")
+
+// ErrDiskStatUnsupported is returned by StatFilesystem on platforms without statfs
+var ErrDiskStatUnsupported = errors.New("disk space checks are not supported on this platform")
+
+// StatFS returns the bytes available to the process on the filesystem holding path
+type StatFS func(path string) (uint64, error)
+
+// DiskSpace reports the free space on the filesystem holding the database file
+type DiskSpace struct {
+	Path           string `json:"path"`
+	AvailableBytes uint64 `json:"available_bytes"`
+	MinFreeBytes   int64  `json:"min_free_bytes"`
+}
+
+// CheckDiskSpace reports the free space on the filesystem holding the SQLite
+// database at dsn, failing with ErrLowDiskSpace below minFree. A zero minFree only
+// reports. In-memory databases have no file, so nil is returned without a check.
+func CheckDiskSpace(dsn string, minFree int64, stat StatFS) (*DiskSpace, error) {
+	path, isFile := sqliteFilePath(dsn)
+	if !isFile {
+		return nil, nil
+	}
+
+	// Stat the directory so the check works before the file is created
+	available, err := stat(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	space := &DiskSpace{Path: path, AvailableBytes: available, MinFreeBytes: minFree}
+	if minFree > 0 && available < uint64(minFree) {
+		return space, ErrLowDiskSpace
+	}
+	return space, nil
+}
+
+// sqliteFilePath returns the file an SQLite DSN such as users.db or
+// file:users.db?cache=shared opens, and false for in-memory databases
+func sqliteFilePath(dsn string) (string, bool) {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	if path == "" || strings.HasPrefix(path, ":memory:") || strings.Contains(query, "mode=memory") {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package database
+
+// StatFilesystem returns ErrDiskStatUnsupported on platforms without statfs
+func StatFilesystem(path string) (uint64, error) {
+	return 0, ErrDiskStatUnsupported
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package database
+
+import "syscall"
+
+// StatFilesystem returns the bytes available to unprivileged users on the filesystem holding path
+func StatFilesystem(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
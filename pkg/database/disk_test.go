@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeStatFS reports a fixed amount of free space and records the path it was asked about
+func fakeStatFS(available uint64, statted *string) StatFS {
+	return func(path string) (uint64, error) {
+		*statted = path
+		return available, nil
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	tests := []struct {
+		name      string
+		dsn       string
+		available uint64
+		minFree   int64
+		statted   string
+		err       error
+	}{
+		{"above threshold", "data/users.db", 200, 100, "data", nil},
+		{"below threshold", "data/users.db", 50, 100, "data", ErrLowDiskSpace},
+		{"zero threshold only reports", "users.db", 0, 0, ".", nil},
+		{"file dsn with query", "file:/var/lib/app/users.db?cache=shared", 200, 100, "/var/lib/app", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var statted string
+
+			// Act
+			space, err := CheckDiskSpace(tt.dsn, tt.minFree, fakeStatFS(tt.available, &statted))
+
+			// Assert
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.statted, statted)
+			assert.Equal(t, tt.available, space.AvailableBytes)
+			assert.Equal(t, tt.minFree, space.MinFreeBytes)
+		})
+	}
+}
+
+func TestCheckDiskSpace_InMemory(t *testing.T) {
+	for _, dsn := range []string{":memory:", "file::memory:?cache=shared", "file:test?mode=memory&cache=shared"} {
+		t.Run(dsn, func(t *testing.T) {
+			// Arrange
+			stat := func(path string) (uint64, error) {
+				t.Fatalf("stat called for in-memory database %q", dsn)
+				return 0, nil
+			}
+
+			// Act
+			space, err := CheckDiskSpace(dsn, 100, stat)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Nil(t, space)
+		})
+	}
+}
+
+func TestCheckDiskSpace_StatError(t *testing.T) {
+	// Arrange
+	stat := func(path string) (uint64, error) { return 0, errors.New("permission denied") }
+
+	// Act
+	space, err := CheckDiskSpace("users.db", 100, stat)
+
+	// Assert
+	assert.EqualError(t, err, "permission denied")
+	assert.Nil(t, space)
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// piiPrefix marks a column value sealed by PIICipher
+const piiPrefix = "enc:v1:"
+
+// PIICipher encrypts personal data at rest with AES-GCM and hashes emails so
+// encrypted rows can still be looked up by email
+type PIICipher struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// NewPIICipher creates a cipher from a base64-encoded 32-byte key
+func NewPIICipher(encodedKey string) (*PIICipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PII encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid PII encryption key: want 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hash with a key derived from, not equal to, the encryption key
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("email-hash"))
+	return &PIICipher{aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// Encrypt seals plaintext with a random nonce. Empty values are stored as is.
+func (c *PIICipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return piiPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt. Values without the encrypted prefix
+// were written before encryption was enabled and are returned unchanged.
+func (c *PIICipher) Decrypt(value string) (string, error) {
+	encoded, sealed := strings.CutPrefix(value, piiPrefix)
+	if !sealed {
+		return value, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt PII: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("failed to decrypt PII: ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt PII: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// HashEmail returns a keyed hash of the email for equality lookups, compared case-insensitively
+func (c *PIICipher) HashEmail(email string) string {
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// piiContextKey carries the cipher used by the pii serializer in a statement context
+type piiContextKey struct{}
+
+func init() {
+	schema.RegisterSerializer("pii", piiSerializer{})
+}
+
+// piiSerializer encrypts string fields tagged serializer:pii on write and decrypts
+// them on read, using the cipher in the statement context. Without a cipher
+// values pass through unchanged.
+type piiSerializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (piiSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported value %T for PII field %s", dbValue, field.Name)
+	}
+
+	if c, ok := ctx.Value(piiContextKey{}).(*PIICipher); ok {
+		var err error
+		if value, err = c.Decrypt(value); err != nil {
+			return err
+		}
+	}
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (piiSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, _ := fieldValue.(string)
+	if c, ok := ctx.Value(piiContextKey{}).(*PIICipher); ok {
+		return c.Encrypt(value)
+	}
+	return value, nil
+}
+
+// withPII returns a session of conn whose statements encrypt PII with c
+func withPII(conn *gorm.DB, c *PIICipher) *gorm.DB {
+	return conn.WithContext(context.WithValue(conn.Statement.Context, piiContextKey{}, c))
+}
+
+// EnablePIIEncryption encrypts the fields tagged serializer:pii with c on the
+// primary and any read replica
+func (db *DB) EnablePIIEncryption(c *PIICipher) {
+	db.PII = c
+	db.DB = withPII(db.DB, c)
+	if db.Replica != nil {
+		db.Replica = withPII(db.Replica, c)
+	}
+}
+
+// HashEmail returns the lookup hash stored alongside an email, or "" when PII
+// encryption is off and emails are looked up directly
+func (db *DB) HashEmail(email string) string {
+	if db.PII == nil {
+		return ""
+	}
+	return db.PII.HashEmail(email)
+}
+
+// EncryptPlaintextPII encrypts the emails and phone numbers of users written
+// before encryption was enabled, or while it was off, and fills in their email
+// hashes. It returns how many users were updated.
+func (db *DB) EncryptPlaintextPII() (int, error) {
+	if db.PII == nil {
+		return 0, nil
+	}
+
+	// Query the table directly so values are read and written without the serializer
+	var rows []struct {
+		ID    uint
+		Email string
+		Phone string
+	}
+	err := db.Table("users").
+		Select("id, email, COALESCE(phone, '') AS phone").
+		Where("COALESCE(email_hash, '') = '' OR (COALESCE(phone, '') <> '' AND phone NOT LIKE ?)", piiPrefix+"%").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			email, err := db.PII.Decrypt(row.Email)
+			if err != nil {
+				return err
+			}
+			phone, err := db.PII.Decrypt(row.Phone)
+			if err != nil {
+				return err
+			}
+
+			encryptedEmail, err := db.PII.Encrypt(email)
+			if err != nil {
+				return err
+			}
+			encryptedPhone, err := db.PII.Encrypt(phone)
+			if err != nil {
+				return err
+			}
+			err = tx.Table("users").Where("id = ?", row.ID).UpdateColumns(map[string]interface{}{
+				"email":      encryptedEmail,
+				"phone":      encryptedPhone,
+				"email_hash": db.PII.HashEmail(email),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt plaintext PII: %w", err)
+	}
+	return len(rows), nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// testPIIKey is a base64-encoded 32-byte key for tests
+const testPIIKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestPIICipher_RoundTrip(t *testing.T) {
+	// Arrange
+	c, err := NewPIICipher(testPIIKey)
+	assert.NoError(t, err)
+
+	// Act
+	first, err := c.Encrypt("john@example.com")
+	assert.NoError(t, err)
+	second, err := c.Encrypt("john@example.com")
+	assert.NoError(t, err)
+	decrypted, err := c.Decrypt(first)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", decrypted)
+	assert.True(t, strings.HasPrefix(first, piiPrefix))
+	assert.NotContains(t, first, "john")
+	assert.NotEqual(t, first, second) // random nonce per value
+}
+
+func TestPIICipher_Decrypt(t *testing.T) {
+	// Arrange
+	c, err := NewPIICipher(testPIIKey)
+	assert.NoError(t, err)
+	sealed, err := c.Encrypt("081-234-5678")
+	assert.NoError(t, err)
+
+	// Act
+	plaintext, plaintextErr := c.Decrypt("081-234-5678")
+	_, tamperedErr := c.Decrypt(sealed[:len(sealed)-4] + "AAA=")
+	empty, emptyErr := c.Encrypt("")
+
+	// Assert
+	assert.NoError(t, plaintextErr)
+	assert.Equal(t, "081-234-5678", plaintext) // rows written before encryption pass through
+	assert.Error(t, tamperedErr)
+	assert.NoError(t, emptyErr)
+	assert.Empty(t, empty)
+}
+
+func TestPIICipher_HashEmail(t *testing.T) {
+	// Arrange
+	c, err := NewPIICipher(testPIIKey)
+	assert.NoError(t, err)
+	other, err := NewPIICipher("ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=")
+	assert.NoError(t, err)
+
+	// Act & Assert
+	assert.Equal(t, c.HashEmail("john@example.com"), c.HashEmail(" John@Example.com"))
+	assert.NotEqual(t, c.HashEmail("john@example.com"), c.HashEmail("jane@example.com"))
+	assert.NotEqual(t, c.HashEmail("john@example.com"), other.HashEmail("john@example.com"))
+}
+
+func TestNewPIICipher_InvalidKey(t *testing.T) {
+	for _, key := range []string{"not base64!", "c2hvcnQ="} {
+		t.Run(key, func(t *testing.T) {
+			// Act
+			_, err := NewPIICipher(key)
+
+			// Assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncryptPlaintextPII(t *testing.T) {
+	// Arrange
+	db, err := NewDatabase("file:pii?mode=memory&cache=shared")
+	assert.NoError(t, err)
+	assert.NoError(t, db.Create(&domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", MembershipID: "LBK000001"}).Error)
+	assert.NoError(t, db.Create(&domain.User{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", MembershipID: "LBK000002"}).Error)
+	c, err := NewPIICipher(testPIIKey)
+	assert.NoError(t, err)
+	db.EnablePIIEncryption(c)
+
+	// Act
+	migrated, err := db.EncryptPlaintextPII()
+	assert.NoError(t, err)
+	again, err := db.EncryptPlaintextPII()
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 2, migrated)
+	assert.Equal(t, 0, again)
+
+	var raw struct {
+		Email     string
+		Phone     string
+		EmailHash string
+	}
+	assert.NoError(t, db.Table("users").Where("membership_id = ?", "LBK000001").Scan(&raw).Error)
+	assert.True(t, strings.HasPrefix(raw.Email, piiPrefix))
+	assert.True(t, strings.HasPrefix(raw.Phone, piiPrefix))
+	assert.Equal(t, c.HashEmail("john@example.com"), raw.EmailHash)
+
+	var user domain.User
+	assert.NoError(t, db.Where("email_hash = ?", c.HashEmail("john@example.com")).First(&user).Error)
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, "081-234-5678", user.Phone)
+}
@@ -0,0 +1,41 @@
+// Package events delivers domain events to subscribers in the same process.
+package events
+
+import (
+	"sync"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// Subscriber receives published user events
+type Subscriber func(event domain.UserEvent)
+
+// Bus is an in-memory domain.EventPublisher. Events are handed to every
+// subscriber in the order they subscribed, before Publish returns.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []Subscriber
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn to receive every event published from now on
+func (b *Bus) Subscribe(fn Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Publish hands event to each subscriber in turn
+func (b *Bus) Publish(event domain.UserEvent) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
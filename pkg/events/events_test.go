@@ -0,0 +1,40 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+func TestBus_Publish(t *testing.T) {
+	// Arrange
+	bus := NewBus()
+	var received []string
+	bus.Subscribe(func(event domain.UserEvent) {
+		received = append(received, "first:"+event.Type)
+	})
+	bus.Subscribe(func(event domain.UserEvent) {
+		received = append(received, "second:"+event.Type)
+	})
+
+	// Act
+	bus.Publish(domain.UserEvent{Type: domain.EventUserCreated, UserID: 1})
+	bus.Publish(domain.UserEvent{Type: domain.EventUserDeleted, UserID: 1})
+
+	// Assert
+	assert.Equal(t, []string{
+		"first:user.created", "second:user.created",
+		"first:user.deleted", "second:user.deleted",
+	}, received)
+}
+
+func TestBus_Publish_NoSubscribers(t *testing.T) {
+	// Arrange
+	bus := NewBus()
+
+	// Act & Assert
+	assert.NotPanics(t, func() {
+		bus.Publish(domain.UserEvent{Type: domain.EventUserCreated, UserID: 1})
+	})
+}
@@ -0,0 +1,62 @@
+// Package logging builds the structured application logger and carries the
+// request correlation ID through a context.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ErrInvalidConfig is returned for an unknown log format or level
+var ErrInvalidConfig = errors.New("invalid log config")
+
+// New returns a logger writing to w in format (FormatText or FormatJSON) that
+// drops records below level (debug, info, warn or error)
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("%w: level %q", ErrInvalidConfig, level)
+	}
+
+	opts := &slog.HandlerOptions{Level: minLevel}
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("%w: format %q", ErrInvalidConfig, format)
+}
+
+// requestIDKey carries the request correlation ID in a context
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request correlation ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request correlation ID carried by ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the request correlation
+// ID when ctx carries one
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
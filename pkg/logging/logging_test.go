@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		level  string
+		err    bool
+	}{
+		{"text", FormatText, "info", false},
+		{"json", FormatJSON, "debug", false},
+		{"level is case-insensitive", FormatJSON, "WARN", false},
+		{"unknown format", "xml", "info", true},
+		{"unknown level", FormatText, "verbose", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			logger, err := New(&bytes.Buffer{}, tt.format, tt.level)
+
+			// Assert
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.Nil(t, logger)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, logger)
+			}
+		})
+	}
+}
+
+func TestNew_JSONLevel(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, "warn")
+	assert.NoError(t, err)
+
+	// Act
+	logger.Info("skipped")
+	logger.Warn("kept", "user_id", 7)
+
+	// Assert
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "kept", record["msg"])
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, float64(7), record["user_id"])
+}
+
+func TestFromContext(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	ctx := WithRequestID(context.Background(), "req-123")
+
+	// Act
+	FromContext(ctx).Error("failed")
+
+	// Assert
+	assert.Equal(t, "req-123", RequestID(ctx))
+	assert.Equal(t, "", RequestID(context.Background()))
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-123", record["request_id"])
+}
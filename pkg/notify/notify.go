@@ -0,0 +1,81 @@
+// Package notify sends messages to users about their accounts.
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// Noop is the notifier used when no mail server is configured; it sends nothing
+type Noop struct{}
+
+// SendWelcome does nothing
+func (Noop) SendWelcome(user *domain.User) error {
+	return nil
+}
+
+// SMTPConfig holds the mail server welcome emails are sent through
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Username and Password authenticate with PLAIN auth; an empty Username sends without auth
+	Username string
+	Password string
+	From     string
+}
+
+// SendMailFunc sends msg from one address to others, matching smtp.SendMail
+type SendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// SMTP sends welcome emails through a mail server
+type SMTP struct {
+	addr     string
+	from     string
+	auth     smtp.Auth
+	sendMail SendMailFunc
+}
+
+// NewSMTP creates a notifier that sends through the server in cfg. A nil
+// sendMail uses smtp.SendMail.
+func NewSMTP(cfg SMTPConfig, sendMail SendMailFunc) *SMTP {
+	if sendMail == nil {
+		sendMail = smtp.SendMail
+	}
+	n := &SMTP{
+		addr:     net.JoinHostPort(cfg.Host, cfg.Port),
+		from:     cfg.From,
+		sendMail: sendMail,
+	}
+	if cfg.Username != "" {
+		n.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return n
+}
+
+// SendWelcome emails user a welcome message with their membership ID
+func (n *SMTP) SendWelcome(user *domain.User) error {
+	if err := n.sendMail(n.addr, n.auth, n.from, []string{user.Email}, welcomeMessage(n.from, user)); err != nil {
+		return fmt.Errorf("send welcome email: %w", err)
+	}
+	return nil
+}
+
+// welcomeMessage builds the welcome email to user, headers included
+func welcomeMessage(from string, user *domain.User) []byte {
+	lines := []string{
+		"From: " + from,
+		"To: " + user.Email,
+		"Subject: Welcome to the membership program",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Hi %s,", user.FirstName),
+		"",
+		fmt.Sprintf("Welcome! Your %s membership ID is %s and you start with %d points.", user.MembershipType, user.MembershipID, user.Points),
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"errors"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+func TestSMTP_SendWelcome(t *testing.T) {
+	// Arrange
+	var addr, from string
+	var to []string
+	var msg []byte
+	var auth smtp.Auth
+	sendMail := func(a string, au smtp.Auth, f string, t []string, m []byte) error {
+		addr, auth, from, to, msg = a, au, f, t, m
+		return nil
+	}
+	notifier := NewSMTP(SMTPConfig{Host: "mail.example.com", Port: "587", Username: "app", Password: "secret", From: "members@example.com"}, sendMail)
+	user := &domain.User{FirstName: "John", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 100}
+
+	// Act
+	err := notifier.SendWelcome(user)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "mail.example.com:587", addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, "members@example.com", from)
+	assert.Equal(t, []string{"john@example.com"}, to)
+	assert.Contains(t, string(msg), "To: john@example.com\r\n")
+	assert.Contains(t, string(msg), "Hi John,")
+	assert.Contains(t, string(msg), "LBK123456")
+}
+
+func TestSMTP_SendWelcome_Error(t *testing.T) {
+	// Arrange
+	refused := errors.New("connection refused")
+	notifier := NewSMTP(SMTPConfig{Host: "mail.example.com", Port: "25"}, func(string, smtp.Auth, string, []string, []byte) error {
+		return refused
+	})
+
+	// Act
+	err := notifier.SendWelcome(&domain.User{Email: "john@example.com"})
+
+	// Assert
+	assert.ErrorIs(t, err, refused)
+}
@@ -0,0 +1,130 @@
+// Package pagination holds the page/limit handling shared by list endpoints.
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Limits applied to the page size
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Params identifies one page of a list
+type Params struct {
+	Page  int
+	Limit int
+}
+
+// NewParams clamps page to at least 1 and limit to 1..MaxLimit, using
+// DefaultLimit when limit is not positive
+func NewParams(page, limit int) Params {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return Params{Page: page, Limit: limit}
+}
+
+// FromQuery reads page and limit from the query string. ok is false when
+// neither parameter is present so endpoints can keep returning the full list.
+func FromQuery(c *fiber.Ctx) (params Params, ok bool) {
+	if c.Query("page") == "" && c.Query("limit") == "" {
+		return Params{}, false
+	}
+	return NewParams(c.QueryInt("page", 1), c.QueryInt("limit", DefaultLimit)), true
+}
+
+// ErrInvalidCursor is returned for a cursor that is not an item id
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor identifies one page of a list ordered by id: up to Limit items with
+// an id greater than After
+type Cursor struct {
+	After uint
+	Limit int
+}
+
+// CursorFromQuery reads cursor and limit from the query string, clamping limit
+// as NewParams does. ok is false when no cursor parameter is present, so
+// endpoints can fall back to page/limit. An empty cursor starts from the beginning.
+func CursorFromQuery(c *fiber.Ctx) (cursor Cursor, ok bool, err error) {
+	value, present := c.Queries()["cursor"]
+	if !present {
+		return Cursor{}, false, nil
+	}
+	cursor.Limit = NewParams(1, c.QueryInt("limit", DefaultLimit)).Limit
+	if value != "" {
+		after, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return Cursor{}, true, fmt.Errorf("%w: %s", ErrInvalidCursor, value)
+		}
+		cursor.After = uint(after)
+	}
+	return cursor, true, nil
+}
+
+// Offset returns the number of items before the page
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Meta describes the current page of a paginated list response
+type Meta struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// NewMeta builds the pagination block for a response. An empty list still has one page.
+func NewMeta(params Params, total int64) Meta {
+	totalPages := int((total + int64(params.Limit) - 1) / int64(params.Limit))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	return Meta{
+		Page:       params.Page,
+		Limit:      params.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// SetLinkHeader writes RFC 5988 first/prev/next/last links for the current
+// page, keeping every other query parameter of the request intact
+func SetLinkHeader(c *fiber.Ctx, meta Meta) {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return
+	}
+
+	link := func(page int, rel string) string {
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(meta.Limit))
+		return fmt.Sprintf(`<%s%s?%s>; rel="%s"`, c.BaseURL(), c.Path(), query.Encode(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if meta.Page > 1 {
+		links = append(links, link(meta.Page-1, "prev"))
+	}
+	if meta.Page < meta.TotalPages {
+		links = append(links, link(meta.Page+1, "next"))
+	}
+	links = append(links, link(meta.TotalPages, "last"))
+
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
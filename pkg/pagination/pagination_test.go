@@ -0,0 +1,152 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     int
+		limit    int
+		expected Params
+	}{
+		{"valid", 3, 50, Params{Page: 3, Limit: 50}},
+		{"defaults", 0, 0, Params{Page: 1, Limit: DefaultLimit}},
+		{"negative", -2, -5, Params{Page: 1, Limit: DefaultLimit}},
+		{"limit clamped", 1, 1000, Params{Page: 1, Limit: MaxLimit}},
+		{"limit at max", 1, MaxLimit, Params{Page: 1, Limit: MaxLimit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewParams(tt.page, tt.limit))
+		})
+	}
+}
+
+func TestParams_Offset(t *testing.T) {
+	assert.Equal(t, 0, Params{Page: 1, Limit: 20}.Offset())
+	assert.Equal(t, 40, Params{Page: 3, Limit: 20}.Offset())
+}
+
+func TestNewMeta_TotalPages(t *testing.T) {
+	tests := []struct {
+		total      int64
+		limit      int
+		totalPages int
+	}{
+		{0, 20, 1},
+		{1, 20, 1},
+		{20, 20, 1},
+		{21, 20, 2},
+		{100, 30, 4},
+	}
+
+	for _, tt := range tests {
+		meta := NewMeta(Params{Page: 1, Limit: tt.limit}, tt.total)
+		assert.Equal(t, tt.totalPages, meta.TotalPages, "total %d limit %d", tt.total, tt.limit)
+		assert.Equal(t, tt.total, meta.Total)
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Params
+		ok       bool
+	}{
+		{"", Params{}, false},
+		{"?membership_type=Gold", Params{}, false},
+		{"?page=2", Params{Page: 2, Limit: DefaultLimit}, true},
+		{"?limit=500", Params{Page: 1, Limit: MaxLimit}, true},
+		{"?page=abc&limit=5", Params{Page: 1, Limit: 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Arrange
+			var params Params
+			var ok bool
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				params, ok = FromQuery(c)
+				return nil
+			})
+
+			// Act
+			_, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+func TestCursorFromQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Cursor
+		ok       bool
+		err      error
+	}{
+		{"?page=2", Cursor{}, false, nil},
+		{"?cursor=", Cursor{Limit: DefaultLimit}, true, nil},
+		{"?cursor=42&limit=5", Cursor{After: 42, Limit: 5}, true, nil},
+		{"?cursor=42&limit=500", Cursor{After: 42, Limit: MaxLimit}, true, nil},
+		{"?cursor=abc", Cursor{}, true, ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Arrange
+			var cursor Cursor
+			var ok bool
+			var parseErr error
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				cursor, ok, parseErr = CursorFromQuery(c)
+				return nil
+			})
+
+			// Act
+			_, err := app.Test(httptest.NewRequest("GET", "/"+tt.query, nil))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, cursor)
+			if tt.err != nil {
+				assert.ErrorIs(t, parseErr, tt.err)
+			} else {
+				assert.NoError(t, parseErr)
+			}
+		})
+	}
+}
+
+func TestSetLinkHeader(t *testing.T) {
+	// Arrange
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		SetLinkHeader(c, NewMeta(Params{Page: 2, Limit: 10}, 35))
+		return nil
+	})
+
+	// Act
+	resp, err := app.Test(httptest.NewRequest("GET", "http://example.com/users?page=2&limit=10&membership_type=Gold", nil))
+
+	// Assert
+	assert.NoError(t, err)
+	link := resp.Header.Get("Link")
+	assert.Contains(t, link, `<http://example.com/users?limit=10&membership_type=Gold&page=1>; rel="first"`)
+	assert.Contains(t, link, `page=1>; rel="prev"`)
+	assert.Contains(t, link, `page=3>; rel="next"`)
+	assert.Contains(t, link, `page=4>; rel="last"`)
+}
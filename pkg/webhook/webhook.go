@@ -0,0 +1,189 @@
+// Package webhook delivers user events to external receivers over HTTP.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// Headers sent with every delivery
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader carries the event type, e.g. user.created
+	EventHeader = "X-Webhook-Event"
+)
+
+// ErrMissingSecret is returned when webhooks are configured without a signing
+// secret, as receivers could not tell deliveries from forgeries
+var ErrMissingSecret = errors.New("webhook secret is required")
+
+// Config controls where and how events are delivered
+type Config struct {
+	URLs   []string
+	Secret string
+	// MaxAttempts caps the tries per URL and event, the first included
+	MaxAttempts int
+	// Backoff is the wait before the first retry; it doubles on each further retry
+	Backoff time.Duration
+	// QueueSize bounds how many events may wait for delivery to each URL; further events are dropped
+	QueueSize int
+	Client    *http.Client
+}
+
+// Defaults for settings left at zero
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Second
+	defaultQueueSize   = 1000
+	defaultTimeout     = 10 * time.Second
+)
+
+// Dispatcher queues user events and POSTs each to every configured URL in the
+// background, retrying failed deliveries with exponential backoff. Each URL has
+// its own queue and worker, so a receiver that is down only delays its own deliveries.
+type Dispatcher struct {
+	cfg     Config
+	workers []*worker
+}
+
+// worker delivers the events queued for one URL in order
+type worker struct {
+	url   string
+	queue chan domain.UserEvent
+}
+
+// NewDispatcher creates a dispatcher for cfg, failing with ErrMissingSecret
+// when URLs are set without a secret. Events are only delivered once Run is started.
+func NewDispatcher(cfg Config) (*Dispatcher, error) {
+	if len(cfg.URLs) > 0 && cfg.Secret == "" {
+		return nil, ErrMissingSecret
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	d := &Dispatcher{cfg: cfg}
+	for _, url := range cfg.URLs {
+		d.workers = append(d.workers, &worker{url: url, queue: make(chan domain.UserEvent, cfg.QueueSize)})
+	}
+	return d, nil
+}
+
+// Enqueue queues event for delivery to every URL without waiting. It is meant to
+// be subscribed to the event bus; a URL whose queue is full drops the event, which is logged.
+func (d *Dispatcher) Enqueue(event domain.UserEvent) {
+	for _, w := range d.workers {
+		select {
+		case w.queue <- event:
+		default:
+			slog.Warn("webhook queue full, dropping event", "url", w.url, "type", event.Type, "user_id", event.UserID)
+		}
+	}
+}
+
+// Run delivers queued events, in order for each URL, until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, w := range d.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx, w)
+		}()
+	}
+	wg.Wait()
+}
+
+// work delivers the events queued for w until ctx is done
+func (d *Dispatcher) work(ctx context.Context, w *worker) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-w.queue:
+			d.deliver(ctx, w.url, event)
+		}
+	}
+}
+
+// deliver sends event to url, logging it when url never accepted it
+func (d *Dispatcher) deliver(ctx context.Context, url string, event domain.UserEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("webhook payload", "type", event.Type, "user_id", event.UserID, "error", err)
+		return
+	}
+
+	if err := d.send(ctx, url, event.Type, body, Sign(d.cfg.Secret, body)); err != nil {
+		slog.Warn("webhook delivery failed", "url", url, "id", event.ID, "type", event.Type, "user_id", event.UserID, "error", err)
+	}
+}
+
+// send POSTs body to url, retrying until it is accepted, the attempts run out or ctx is done
+func (d *Dispatcher) send(ctx context.Context, url, eventType string, body []byte, signature string) error {
+	wait := d.cfg.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = d.post(ctx, url, eventType, body, signature); err == nil || attempt == d.cfg.MaxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post makes one delivery attempt, failing on any status outside 2xx
+func (d *Dispatcher) post(ctx context.Context, url, eventType string, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(SignatureHeader, signature)
+
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by the
+// hex HMAC-SHA256 of body keyed with secret. Receivers recompute it to verify a delivery.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
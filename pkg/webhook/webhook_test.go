@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"kbtg.tech/ai-backend-workshop/internal/domain"
+)
+
+// delivery is one request received by the test server
+type delivery struct {
+	body      []byte
+	eventType string
+	signature string
+}
+
+func TestDispatcher_Delivers(t *testing.T) {
+	// Arrange
+	received := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{body: body, eventType: r.Header.Get(EventHeader), signature: r.Header.Get(SignatureHeader)}
+	}))
+	defer server.Close()
+
+	dispatcher, err := NewDispatcher(Config{URLs: []string{server.URL}, Secret: "s3cret"})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	occurredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Act
+	dispatcher.Enqueue(domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, UserID: 42, OccurredAt: occurredAt})
+
+	// Assert
+	var got delivery
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery received")
+	}
+	var event domain.UserEvent
+	assert.NoError(t, json.Unmarshal(got.body, &event))
+	assert.Equal(t, domain.UserEvent{ID: "evt-1", Type: domain.EventUserCreated, UserID: 42, OccurredAt: occurredAt}, event)
+	assert.Equal(t, domain.EventUserCreated, got.eventType)
+	assert.Equal(t, Sign("s3cret", got.body), got.signature)
+	assert.NotEqual(t, Sign("other", got.body), got.signature)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	// Arrange
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		close(done)
+	}))
+	defer server.Close()
+
+	dispatcher, err := NewDispatcher(Config{URLs: []string{server.URL}, Secret: "s3cret", MaxAttempts: 3, Backoff: time.Millisecond})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	// Act
+	dispatcher.Enqueue(domain.UserEvent{Type: domain.EventUserDeleted, UserID: 1})
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not retried")
+	}
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestDispatcher_FailingURLDoesNotDelayOthers(t *testing.T) {
+	// Arrange
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	received := make(chan struct{}, 2)
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer up.Close()
+
+	dispatcher, err := NewDispatcher(Config{URLs: []string{down.URL, up.URL}, Secret: "s3cret", MaxAttempts: 3, Backoff: time.Minute})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+
+	// Act
+	dispatcher.Enqueue(domain.UserEvent{Type: domain.EventUserCreated, UserID: 1})
+	dispatcher.Enqueue(domain.UserEvent{Type: domain.EventUserUpdated, UserID: 1})
+
+	// Assert
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("delivery held up by a failing URL")
+		}
+	}
+}
+
+func TestNewDispatcher_RequiresSecret(t *testing.T) {
+	// Act
+	dispatcher, err := NewDispatcher(Config{URLs: []string{"https://example.com/hook"}})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrMissingSecret)
+	assert.Nil(t, dispatcher)
+}
+
+func TestSign(t *testing.T) {
+	// Act
+	signature := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+
+	// Assert
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", signature)
+}
@@ -2,10 +2,16 @@ package tests
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"kbtg.tech/ai-backend-workshop/internal/config"
 	"kbtg.tech/ai-backend-workshop/internal/domain"
@@ -20,6 +26,11 @@ import (
 	"gorm.io/gorm"
 )
 
+// errorResponse decodes the error envelope every endpoint responds with
+type errorResponse struct {
+	Error handler.ErrorBody `json:"error"`
+}
+
 type APITestSuite struct {
 	suite.Suite
 	app    *fiber.App
@@ -27,6 +38,16 @@ type APITestSuite struct {
 	config *config.Config
 }
 
+// testAdminKey is the admin key the suite's admin routes accept
+const testAdminKey = "test-admin-key"
+
+// adminRequest builds a request to an admin route carrying the admin key
+func adminRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("X-Admin-Key", testAdminKey)
+	return req
+}
+
 func (suite *APITestSuite) SetupSuite() {
 	// Create test database
 	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
@@ -34,41 +55,46 @@ func (suite *APITestSuite) SetupSuite() {
 
 	suite.db = &database.DB{DB: gormDB}
 
+	// Every connection to :memory: opens a separate database, so keep to one
+	sqlDB, err := gormDB.DB()
+	suite.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+
 	// Migrate schema
-	err = suite.db.AutoMigrate(&domain.User{})
+	err = suite.db.AutoMigrate(&domain.User{}, &domain.PointsTransaction{}, &domain.TierChange{}, &domain.RevokedMembershipID{}, &domain.IdempotencyKey{})
 	suite.Require().NoError(err)
 
 	// Setup dependencies
+	suite.config = &config.Config{AdminAPIKeys: []string{testAdminKey}}
 	userRepo := repository.NewUserRepository(suite.db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	userUseCase := usecase.NewUserUseCase(userRepo, suite.config)
 	userHandler := handler.NewUserHandler(userUseCase)
+	adminHandler := handler.NewAdminHandler(userUseCase, suite.config)
+	pointsHandler := handler.NewPointsHandler(usecase.NewPointsUseCase(userRepo, repository.NewPointsTransactionRepository(suite.db)))
+	healthHandler := handler.NewHealthHandler(suite.config, suite.db.Ping, database.StatFilesystem)
 
 	// Setup Fiber app
 	suite.app = fiber.New(fiber.Config{
 		DisableStartupMessage: true,
+		ErrorHandler:          handler.ErrorHandler,
 	})
 
 	// Setup routes
 	api := suite.app.Group("/api/v1")
 
-	api.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"message": "KBTG AI Backend Workshop is running!",
-		})
-	})
-
-	users := api.Group("/users")
-	users.Get("/", userHandler.GetUsers)
-	users.Get("/:id", userHandler.GetUser)
-	users.Post("/", userHandler.CreateUser)
-	users.Put("/:id", userHandler.UpdateUser)
-	users.Delete("/:id", userHandler.DeleteUser)
+	healthHandler.RegisterRoutes(api)
+	userHandler.RegisterRoutes(api)
+	adminHandler.RegisterRoutes(api)
+	pointsHandler.RegisterRoutes(api)
 }
 
 func (suite *APITestSuite) TearDownTest() {
 	// Clean database between tests
 	suite.db.Exec("DELETE FROM users")
+	suite.db.Exec("DELETE FROM points_transactions")
+	suite.db.Exec("DELETE FROM tier_changes")
+	suite.db.Exec("DELETE FROM revoked_membership_ids")
+	suite.db.Exec("DELETE FROM idempotency_keys")
 }
 
 func (suite *APITestSuite) TestHealthEndpoint() {
@@ -202,10 +228,10 @@ func (suite *APITestSuite) TestGetUserByID_NotFound() {
 	suite.NoError(err)
 	suite.Equal(404, resp.StatusCode)
 
-	var response map[string]interface{}
+	var response errorResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	suite.NoError(err)
-	suite.Equal("User not found", response["error"])
+	suite.Equal(handler.ErrorBody{Code: "USER_NOT_FOUND", Message: "User not found"}, response.Error)
 }
 
 func (suite *APITestSuite) TestUpdateUser() {
@@ -221,9 +247,10 @@ func (suite *APITestSuite) TestUpdateUser() {
 	err := suite.db.Create(&user).Error
 	suite.Require().NoError(err)
 
+	points := 200
 	updateReq := domain.UpdateUserRequest{
 		FirstName: "Jane",
-		Points:    200,
+		Points:    &points,
 	}
 
 	body, err := json.Marshal(updateReq)
@@ -248,6 +275,86 @@ func (suite *APITestSuite) TestUpdateUser() {
 	suite.Equal(float64(200), data["points"])
 }
 
+func (suite *APITestSuite) TestUpdateUser_ZeroPoints() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	update := func(body string) int {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data.Points
+	}
+
+	// Act & Assert
+	suite.Equal(100, update(`{"first_name": "Jane"}`))
+	suite.Equal(0, update(`{"points": 0}`))
+}
+
+func (suite *APITestSuite) TestUpdateUser_ReplaceSemantics() {
+	// Arrange
+	suite.config.UpdateSemantics = config.UpdateSemanticsReplace
+	defer func() { suite.config.UpdateSemantics = "" }()
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", MembershipID: "LBK123456", Points: 100}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	update := func(body string) (int, domain.User) {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response.Data
+	}
+
+	// Act
+	partialStatus, _ := update(`{"points": 0}`)
+	fullStatus, replaced := update(`{"first_name": "Jane", "last_name": "Doe", "email": "john@example.com"}`)
+
+	// Assert
+	suite.Equal(400, partialStatus)
+	suite.Equal(200, fullStatus)
+	suite.Equal("Jane", replaced.FirstName)
+	suite.Equal(0, replaced.Points)
+	suite.Empty(replaced.Phone)
+}
+
+func (suite *APITestSuite) TestUpdateUser_ClearPhone() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	update := func(body string) string {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data.Phone
+	}
+
+	// Act & Assert
+	suite.Equal("081-234-5678", update(`{"first_name": "Jane"}`))
+	suite.Equal("", update(`{"phone": ""}`))
+}
+
 func (suite *APITestSuite) TestDeleteUser() {
 	// Arrange - Create test user
 	user := domain.User{
@@ -294,10 +401,82 @@ func (suite *APITestSuite) TestCreateUser_ValidationError() {
 	suite.NoError(err)
 	suite.Equal(400, resp.StatusCode)
 
-	var response map[string]interface{}
+	var response errorResponse
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	suite.NoError(err)
-	suite.Contains(response["error"], "required")
+	suite.Equal("VALIDATION_FAILED", response.Error.Code)
+	suite.Equal("validation failed", response.Error.Message)
+	suite.Equal([]domain.FieldError{{Field: "last_name", Message: "is required"}}, response.Error.Details)
+}
+
+func (suite *APITestSuite) TestCreateUser_InvalidEmail() {
+	// Arrange
+	body, err := json.Marshal(domain.CreateUserRequest{Email: "notanemail"})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var response errorResponse
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal([]domain.FieldError{
+		{Field: "first_name", Message: "is required"},
+		{Field: "last_name", Message: "is required"},
+		{Field: "email", Message: "must be a valid email address"},
+	}, response.Error.Details)
+}
+
+func (suite *APITestSuite) TestUpdateUser_InvalidEmail() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	body, _ := json.Marshal(domain.UpdateUserRequest{Email: "notanemail"})
+
+	// Act
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var response errorResponse
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal([]domain.FieldError{{Field: "email", Message: "must be a valid email address"}}, response.Error.Details)
+}
+
+func (suite *APITestSuite) TestCreateUser_DuplicateEmailDifferentCase() {
+	create := func(email string) *http.Response {
+		body, err := json.Marshal(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: email})
+		suite.Require().NoError(err)
+		req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		return resp
+	}
+
+	// Act
+	first := create("john@example.com")
+	second := create("JOHN@EXAMPLE.COM")
+
+	// Assert
+	suite.Equal(201, first.StatusCode)
+	suite.Equal(400, second.StatusCode)
+	var response errorResponse
+	suite.NoError(json.NewDecoder(second.Body).Decode(&response))
+	suite.Equal("EMAIL_TAKEN", response.Error.Code)
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored).Error)
+	suite.Equal("john@example.com", stored.Email)
 }
 
 func (suite *APITestSuite) TestCreateUser_DuplicateEmail() {
@@ -330,10 +509,1831 @@ func (suite *APITestSuite) TestCreateUser_DuplicateEmail() {
 	suite.NoError(err)
 	suite.Equal(400, resp.StatusCode)
 
-	var response map[string]interface{}
+	var response errorResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Equal("EMAIL_TAKEN", response.Error.Code)
+	suite.Contains(response.Error.Message, "already exists")
+}
+
+func (suite *APITestSuite) TestDeleteUser_IfMatch() {
+	// Arrange - Create test user and fetch its ETag
+	user := domain.User{
+		FirstName:    "John",
+		LastName:     "Doe",
+		Email:        "john@example.com",
+		MembershipID: "LBK123456",
+	}
+	err := suite.db.Create(&user).Error
+	suite.Require().NoError(err)
+
+	getResp, err := suite.app.Test(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d", user.ID), nil))
+	suite.Require().NoError(err)
+	etag := getResp.Header.Get("ETag")
+	suite.Require().NotEmpty(etag)
+
+	// Act - stale precondition
+	staleReq := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil)
+	staleReq.Header.Set("If-Match", `"stale"`)
+	staleResp, err := suite.app.Test(staleReq)
+	suite.Require().NoError(err)
+
+	// Act - current precondition
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil)
+	req.Header.Set("If-Match", etag)
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(412, staleResp.StatusCode)
+	suite.Equal(200, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestDeleteUser_IfMatchAfterUpdate() {
+	// Arrange - fetch the ETag, then change the user behind the client's back
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	getResp, err := suite.app.Test(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d", user.ID), nil))
+	suite.Require().NoError(err)
+	etag := getResp.Header.Get("ETag")
+
+	updateReq := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), strings.NewReader(`{"first_name": "Johnny"}`))
+	updateReq.Header.Set("Content-Type", "application/json")
+	updateResp, err := suite.app.Test(updateReq)
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, updateResp.StatusCode)
+
+	// Act
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil)
+	req.Header.Set("If-Match", etag)
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(412, resp.StatusCode)
+	var count int64
+	suite.Require().NoError(suite.db.Model(&domain.User{}).Where("id = ?", user.ID).Count(&count).Error)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *APITestSuite) TestBatchDeleteUsers() {
+	// Arrange - Create two users, one id is missing
+	first := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	second := domain.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457"}
+	suite.Require().NoError(suite.db.Create(&first).Error)
+	suite.Require().NoError(suite.db.Create(&second).Error)
+
+	body, err := json.Marshal(domain.BatchDeleteRequest{IDs: []uint{first.ID, 999, second.ID}})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/batch-delete", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(207, resp.StatusCode)
+
+	var response struct {
+		Data []domain.BatchDeleteResult `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Equal([]domain.BatchDeleteResult{
+		{ID: first.ID, Status: domain.BatchDeleteDeleted},
+		{ID: 999, Status: domain.BatchDeleteNotFound},
+		{ID: second.ID, Status: domain.BatchDeleteDeleted},
+	}, response.Data)
+
+	var count int64
+	suite.db.Model(&domain.User{}).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+func (suite *APITestSuite) TestBatchDeleteUsers_StatusByOutcome() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	type batchResponse struct {
+		Data  []domain.BatchDeleteResult `json:"data"`
+		Error *handler.ErrorBody         `json:"error"`
+	}
+	batchDelete := func(ids ...uint) (int, batchResponse) {
+		body, err := json.Marshal(domain.BatchDeleteRequest{IDs: ids})
+		suite.Require().NoError(err)
+		req := httptest.NewRequest("POST", "/api/v1/users/batch-delete", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+
+		var response batchResponse
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response
+	}
+
+	// Act
+	allFailed, failedBody := batchDelete(998, 999)
+	allSucceeded, succeededBody := batchDelete(user.ID)
+
+	// Assert
+	suite.Equal(400, allFailed)
+	suite.Require().NotNil(failedBody.Error)
+	suite.Equal("BATCH_FAILED", failedBody.Error.Code)
+	suite.Len(failedBody.Data, 2)
+	suite.Equal(200, allSucceeded)
+	suite.Nil(succeededBody.Error)
+}
+
+func (suite *APITestSuite) TestGetTotalPoints() {
+	// Arrange - Seed users across tiers
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipType: "Silver", MembershipID: "LBK123457", Points: 8000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipType: "Gold", MembershipID: "LBK123458", Points: 500},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	totalFor := func(url string) float64 {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response map[string]interface{}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response["data"].(map[string]interface{})["total_points"].(float64)
+	}
+
+	// Act & Assert
+	suite.Equal(float64(23500), totalFor("/api/v1/users/points/total"))
+	suite.Equal(float64(15500), totalFor("/api/v1/users/points/total?membership_type=Gold"))
+	suite.Equal(float64(0), totalFor("/api/v1/users/points/total?membership_type=Bronze"))
+}
+
+func (suite *APITestSuite) TestGetLeaderboard_PerTier() {
+	// Arrange - Seed users across tiers
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipType: "Silver", MembershipID: "LBK123457", Points: 20000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipType: "Gold", MembershipID: "LBK123458", Points: 30000},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/leaderboard?membership_type=Gold", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data []domain.LeaderboardEntry `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Require().Len(response.Data, 2)
+	suite.Equal(1, response.Data[0].Rank)
+	suite.Equal("jim@example.com", response.Data[0].Email)
+	suite.Equal(2, response.Data[1].Rank)
+	suite.Equal("john@example.com", response.Data[1].Email)
+}
+
+func (suite *APITestSuite) TestReissueInvalidMembershipIDs() {
+	// Arrange - one valid and two legacy-format membership IDs
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "MEM-42"},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipID: "LBK12"},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+
+	// Act
+	resp, err := suite.app.Test(adminRequest("POST", "/api/v1/admin/membership-ids/reissue", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data []domain.MembershipIDChange `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Require().Len(response.Data, 2)
+	suite.Equal(users[1].ID, response.Data[0].UserID)
+	suite.Equal("MEM-42", response.Data[0].OldID)
+	suite.Equal(users[2].ID, response.Data[1].UserID)
+
+	var valid, reissued domain.User
+	suite.db.First(&valid, users[0].ID)
+	suite.db.First(&reissued, users[1].ID)
+	suite.Equal("LBK123456", valid.MembershipID)
+	suite.Equal(response.Data[0].NewID, reissued.MembershipID)
+	suite.Regexp(`^LBK\d{6}$`, reissued.MembershipID)
+}
+
+func (suite *APITestSuite) TestImportUsersFromURL() {
+	// Arrange - serve a CSV with two good rows and one bad row
+	csvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		fmt.Fprint(w, "first_name,last_name,email,phone,membership_type,points\n"+
+			"John,Doe,john@example.com,081-111-1111,Gold,100\n"+
+			"Jane,,jane@example.com,,,\n"+
+			"สมชาย,ใจดี,somchai@example.com,,Silver,50\n")
+	}))
+	defer csvServer.Close()
+
+	suite.config.ImportAllowedHosts = []string{"127.0.0.1"}
+	defer func() { suite.config.ImportAllowedHosts = nil }()
+
+	body, err := json.Marshal(domain.ImportURLRequest{URL: csvServer.URL + "/users.csv"})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/import/url", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(207, resp.StatusCode)
+
+	var response struct {
+		Data    []domain.ImportRowResult `json:"data"`
+		Created int                      `json:"created"`
+		Failed  int                      `json:"failed"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(2, response.Created)
+	suite.Equal(1, response.Failed)
+	suite.Require().Len(response.Data, 3)
+	suite.Equal(domain.ImportRowCreated, response.Data[0].Status)
+	suite.Equal(3, response.Data[1].Row)
+	suite.Equal(domain.ImportRowError, response.Data[1].Status)
+	suite.Contains(response.Data[1].Error, "required")
+
+	var imported domain.User
+	suite.Require().NoError(suite.db.Where("email = ?", "somchai@example.com").First(&imported).Error)
+	suite.Equal("สมชาย", imported.FirstName)
+	suite.Equal(50, imported.Points)
+}
+
+func (suite *APITestSuite) TestImportUsersFromURL_Template() {
+	// Arrange - fill the downloaded template with one row
+	resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/import/template.csv", nil))
+	suite.Require().NoError(err)
+	template, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	csvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write(template)
+		fmt.Fprint(w, "John,Doe,john@example.com,081-111-1111,Gold,100\n")
+	}))
+	defer csvServer.Close()
+
+	suite.config.ImportAllowedHosts = []string{"127.0.0.1"}
+	defer func() { suite.config.ImportAllowedHosts = nil }()
+
+	body, err := json.Marshal(domain.ImportURLRequest{URL: csvServer.URL + "/users.csv"})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/import/url", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var imported domain.User
+	suite.Require().NoError(suite.db.Where("email = ?", "john@example.com").First(&imported).Error)
+	suite.Equal("John", imported.FirstName)
+	suite.Equal("Doe", imported.LastName)
+	suite.Equal("0811111111", imported.Phone)
+	suite.Equal("Gold", imported.MembershipType)
+	suite.Equal(100, imported.Points)
+}
+
+func (suite *APITestSuite) TestImportUsersFromURL_HostNotAllowed() {
+	// Arrange
+	body, err := json.Marshal(domain.ImportURLRequest{URL: "http://169.254.169.254/latest/meta-data"})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/import/url", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestGetLeaderboard_MinPoints() {
+	// Arrange - Seed users, one below the threshold
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 0},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", Points: 2000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipID: "LBK123458", Points: 1000},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	leaderboard := func(url string) []domain.LeaderboardEntry {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data []domain.LeaderboardEntry `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data
+	}
+
+	// Act
+	explicit := leaderboard("/api/v1/users/leaderboard?min_points=1500")
+	suite.config.LeaderboardMinPoints = 1
+	configured := leaderboard("/api/v1/users/leaderboard")
+	suite.config.LeaderboardMinPoints = 0
+
+	// Assert
+	suite.Require().Len(explicit, 1)
+	suite.Equal(1, explicit[0].Rank)
+	suite.Equal("jane@example.com", explicit[0].Email)
+
+	suite.Require().Len(configured, 2)
+	suite.Equal(2, configured[1].Rank)
+	suite.Equal("jim@example.com", configured[1].Email)
+}
+
+func (suite *APITestSuite) TestCheckEmailsExist() {
+	// Arrange - Seed one user with a mixed-case email
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "John@Example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	body, _ := json.Marshal(domain.EmailsExistRequest{
+		Emails: []string{" john@example.com ", "JANE@example.com"},
+	})
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/exists", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data map[string]bool `json:"data"`
+	}
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	suite.NoError(err)
-	suite.Contains(response["error"], "already exists")
+	suite.Equal(map[string]bool{
+		"john@example.com": true,
+		"jane@example.com": false,
+	}, response.Data)
+}
+
+func (suite *APITestSuite) TestCheckEmailsExist_DeletedUser() {
+	// Arrange - a deleted user keeps their email reserved by default
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	suite.Require().NoError(suite.db.Delete(&user).Error)
+
+	check := func() map[string]bool {
+		req := httptest.NewRequest("POST", "/api/v1/users/exists", strings.NewReader(`{"emails":["john@example.com"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data map[string]bool `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data
+	}
+
+	// Act
+	reserved := check()
+	suite.config.UniqueExcludesDeleted = true
+	defer func() { suite.config.UniqueExcludesDeleted = false }()
+	freed := check()
+
+	// Assert
+	suite.Equal(map[string]bool{"john@example.com": true}, reserved)
+	suite.Equal(map[string]bool{"john@example.com": false}, freed)
+}
+
+func (suite *APITestSuite) TestCheckEmailsExist_EmptyList() {
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/exists", bytes.NewReader([]byte(`{"emails":[]}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestUpdateUser_ImmutableEmail() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	suite.config.ImmutableFields = []string{"email"}
+	defer func() { suite.config.ImmutableFields = nil }()
+
+	body, _ := json.Marshal(domain.UpdateUserRequest{Email: "johnny@example.com"})
+
+	// Act
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal("john@example.com", stored.Email)
+}
+
+func (suite *APITestSuite) TestSetPoints() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456", Points: 1200}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	body, _ := json.Marshal(map[string]int{"points": 12000})
+
+	// Act
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d/points", user.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data        domain.User              `json:"data"`
+		Transaction domain.PointsTransaction `json:"transaction"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Equal(12000, response.Data.Points)
+	suite.Equal("Gold", response.Data.MembershipType)
+	suite.Equal(10800, response.Transaction.Delta)
+
+	var stored []domain.PointsTransaction
+	suite.Require().NoError(suite.db.Where("user_id = ?", user.ID).Find(&stored).Error)
+	suite.Require().Len(stored, 1)
+	suite.Equal(10800, stored[0].Delta)
+	suite.Equal(12000, stored[0].Balance)
+	suite.Equal(domain.PointsReasonSet, stored[0].Reason)
+}
+
+func (suite *APITestSuite) TestSetPoints_Negative() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 1200}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	// Act
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d/points", user.ID), bytes.NewReader([]byte(`{"points":-5}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var count int64
+	suite.db.Model(&domain.PointsTransaction{}).Count(&count)
+	suite.Equal(int64(0), count)
+}
+
+func (suite *APITestSuite) TestResetUser() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	suite.config.WelcomeBonusPoints = 100
+	defer func() { suite.config.WelcomeBonusPoints = 0 }()
+
+	url := fmt.Sprintf("/api/v1/admin/users/%d/reset", user.ID)
+
+	// Act
+	unauthorized, err := suite.app.Test(httptest.NewRequest("POST", url+"?confirm=true", nil))
+	suite.Require().NoError(err)
+	unconfirmed, err := suite.app.Test(adminRequest("POST", url, nil))
+	suite.Require().NoError(err)
+	resp, err := suite.app.Test(adminRequest("POST", url+"?confirm=true", nil))
+
+	// Assert
+	suite.Equal(401, unauthorized.StatusCode)
+	suite.Equal(400, unconfirmed.StatusCode)
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal(100, stored.Points)
+	suite.Equal("Bronze", stored.MembershipType)
+	suite.Equal("john@example.com", stored.Email)
+	suite.Equal("LBK123456", stored.MembershipID)
+
+	var txns []domain.PointsTransaction
+	suite.Require().NoError(suite.db.Where("user_id = ?", user.ID).Find(&txns).Error)
+	suite.Require().Len(txns, 1)
+	suite.Equal(domain.PointsReasonReset, txns[0].Reason)
+	suite.Equal(-14900, txns[0].Delta)
+}
+
+func (suite *APITestSuite) TestGetDuplicateCandidates() {
+	// Arrange - Seed a near-duplicate pair and an unrelated user
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", Phone: "081-234-5678", MembershipID: "LBK123456"},
+		{FirstName: "Johnny", LastName: "Doe", Email: "johnny@example.com", Phone: "081 234 5678", MembershipID: "LBK123457"},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", Phone: "089-765-4321", MembershipID: "LBK123458"},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+
+	// Act
+	resp, err := suite.app.Test(adminRequest("GET", "/api/v1/admin/users/duplicates", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data []domain.DuplicateCandidate `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Require().Len(response.Data, 1)
+	suite.Equal(users[0].ID, response.Data[0].UserID)
+	suite.Equal(users[1].ID, response.Data[0].OtherUserID)
+	suite.Equal(80, response.Data[0].Score)
+}
+
+func (suite *APITestSuite) TestGetTierHistory() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	upgrade, _ := json.Marshal(domain.UpdateUserRequest{MembershipType: "Silver"})
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), bytes.NewReader(upgrade))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, resp.StatusCode)
+
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d/points", user.ID), bytes.NewReader([]byte(`{"points":12000}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, resp.StatusCode)
+
+	// Act
+	resp, err = suite.app.Test(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d/tier-history", user.ID), nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data []domain.TierChange `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Require().Len(response.Data, 2)
+	suite.Equal("Bronze", response.Data[0].From)
+	suite.Equal("Silver", response.Data[0].To)
+	suite.Equal(domain.TierTriggerUpdate, response.Data[0].Trigger)
+	suite.Equal("Silver", response.Data[1].From)
+	suite.Equal("Gold", response.Data[1].To)
+	suite.Equal(domain.PointsReasonSet, response.Data[1].Trigger)
+	suite.False(response.Data[1].CreatedAt.IsZero())
+}
+
+func (suite *APITestSuite) TestSetPoints_RateLimit() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	suite.config.PointsRateLimit = 1000
+	suite.config.PointsRateWindow = 24 * time.Hour
+	defer func() { suite.config.PointsRateLimit = 0 }()
+
+	setPoints := func(points int) *http.Response {
+		body, _ := json.Marshal(map[string]int{"points": points})
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d/points", user.ID), bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		return resp
+	}
+
+	// Act
+	within := setPoints(800)
+	over := setPoints(1500)
+
+	// Assert
+	suite.Equal(200, within.StatusCode)
+	suite.Equal(429, over.StatusCode)
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal(800, stored.Points)
+}
+
+func (suite *APITestSuite) TestUpdateUser_DirectTierEditDenied() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	suite.config.DirectTierEditsDisabled = true
+	defer func() { suite.config.DirectTierEditsDisabled = false }()
+
+	body, _ := json.Marshal(domain.UpdateUserRequest{MembershipType: "Gold"})
+
+	// Act
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(403, resp.StatusCode)
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal("Bronze", stored.MembershipType)
+}
+
+func (suite *APITestSuite) TestGetStats() {
+	getStats := func() domain.UserStats {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/stats", nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data domain.UserStats `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data
+	}
+
+	// Act - before any users exist
+	empty := getStats()
+
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipType: "Gold", MembershipID: "LBK123458", Points: 12000},
+		{FirstName: "Joe", LastName: "Bloggs", Email: "joe@example.com", MembershipType: "Bronze", MembershipID: "LBK123459", Points: 100},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	// Act
+	stats := getStats()
+
+	// Assert
+	suite.Equal(domain.UserStats{
+		TierCounts: map[string]int64{"Bronze": 0, "Silver": 0, "Gold": 0},
+		Tiers:      []domain.TierStats{},
+	}, empty)
+	suite.Equal(int64(3), stats.TotalUsers)
+	suite.Equal(map[string]int64{"Bronze": 1, "Silver": 0, "Gold": 2}, stats.TierCounts)
+	suite.Equal(int64(27100), stats.TotalPoints)
+	suite.InDelta(9033.33, stats.AveragePoints, 0.01)
+}
+
+func (suite *APITestSuite) TestGetStatsCSV() {
+	// Arrange - Seed users across tiers
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipType: "Silver", MembershipID: "LBK123457", Points: 8000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipType: "Gold", MembershipID: "LBK123458", Points: 12000},
+		{FirstName: "Joe", LastName: "Bloggs", Email: "joe@example.com", MembershipType: "Bronze", MembershipID: "LBK123459", Points: 100},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	readCSV := func(url string) [][]string {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+		suite.Contains(resp.Header.Get("Content-Type"), "text/csv")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		suite.Require().NoError(err)
+		return records
+	}
+
+	// Act
+	all := readCSV("/api/v1/users/stats.csv")
+	gold := readCSV("/api/v1/users/stats.csv?membership_type=Gold")
+
+	// Assert
+	suite.Equal([][]string{
+		domain.TierStatsCSVColumns,
+		{"Bronze", "1", "100", "100.00"},
+		{"Silver", "1", "8000", "8000.00"},
+		{"Gold", "2", "27000", "13500.00"},
+	}, all)
+	suite.Equal([][]string{
+		domain.TierStatsCSVColumns,
+		{"Gold", "2", "27000", "13500.00"},
+	}, gold)
+}
+
+func (suite *APITestSuite) TestGetUsers_Sort() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 500},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", Points: 9000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipID: "LBK123458", Points: 2000},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	emails := func(url string) []string {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data []domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		var result []string
+		for _, user := range response.Data {
+			result = append(result, user.Email)
+		}
+		return result
+	}
+
+	// Act & Assert
+	suite.Equal([]string{"jane@example.com", "jim@example.com", "john@example.com"}, emails("/api/v1/users?sort=points&order=desc"))
+	suite.Equal([]string{"jim@example.com", "john@example.com", "jane@example.com"}, emails("/api/v1/users?sort=last_name"))
+	suite.Equal([]string{"jane@example.com"}, emails("/api/v1/users?sort=points&order=desc&limit=1"))
+	suite.Equal([]string{"john@example.com", "jane@example.com", "jim@example.com"}, emails("/api/v1/users"))
+}
+
+func (suite *APITestSuite) TestGetUsers_Cursor() {
+	// Arrange
+	for i, name := range []string{"ann", "bob", "cat", "dan", "eve"} {
+		user := domain.User{FirstName: name, LastName: "Doe", Email: name + "@example.com", MembershipID: fmt.Sprintf("LBK10000%d", i)}
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	type page struct {
+		Data       []domain.User `json:"data"`
+		NextCursor *uint         `json:"next_cursor"`
+	}
+	getPage := func(url string) page {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response page
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response
+	}
+	names := func(p page) []string {
+		var result []string
+		for _, user := range p.Data {
+			result = append(result, user.FirstName)
+		}
+		return result
+	}
+
+	// Act - a user deleted between pages does not shift the next page
+	first := getPage("/api/v1/users?cursor=&limit=2")
+	suite.Require().NoError(suite.db.Where("first_name = ?", "ann").Delete(&domain.User{}).Error)
+	second := getPage(fmt.Sprintf("/api/v1/users?cursor=%d&limit=2", *first.NextCursor))
+	last := getPage(fmt.Sprintf("/api/v1/users?cursor=%d&limit=2", *second.NextCursor))
+
+	// Assert
+	suite.Equal([]string{"ann", "bob"}, names(first))
+	suite.Equal([]string{"cat", "dan"}, names(second))
+	suite.Equal([]string{"eve"}, names(last))
+	suite.Nil(last.NextCursor)
+}
+
+func (suite *APITestSuite) TestGetUsers_InvalidCursor() {
+	for _, url := range []string{"/api/v1/users?cursor=abc", "/api/v1/users?cursor=1&sort=points"} {
+		// Act
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+
+		// Assert
+		suite.NoError(err)
+		suite.Equal(400, resp.StatusCode, url)
+	}
+}
+
+func (suite *APITestSuite) TestGetUsers_InvalidSort() {
+	for _, url := range []string{"/api/v1/users?sort=password", "/api/v1/users?sort=points&order=sideways"} {
+		// Act
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+
+		// Assert
+		suite.NoError(err)
+		suite.Equal(400, resp.StatusCode, url)
+
+		var response errorResponse
+		suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+		suite.Equal("INVALID_SORT", response.Error.Code, url)
+		suite.Contains(response.Error.Message, "invalid sort")
+	}
+}
+
+func (suite *APITestSuite) TestSimulateTiers() {
+	// Arrange - Seed users whose tiers match the current thresholds
+	users := []domain.User{
+		{FirstName: "A", LastName: "A", Email: "a@example.com", MembershipType: "Bronze", MembershipID: "LBK100001", Points: 1000},
+		{FirstName: "B", LastName: "B", Email: "b@example.com", MembershipType: "Bronze", MembershipID: "LBK100002", Points: 4000},
+		{FirstName: "C", LastName: "C", Email: "c@example.com", MembershipType: "Silver", MembershipID: "LBK100003", Points: 6000},
+		{FirstName: "D", LastName: "D", Email: "d@example.com", MembershipType: "Silver", MembershipID: "LBK100004", Points: 9000},
+		{FirstName: "E", LastName: "E", Email: "e@example.com", MembershipType: "Gold", MembershipID: "LBK100005", Points: 20000},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	body, _ := json.Marshal(domain.TierThresholds{SilverMinPoints: 3000, GoldMinPoints: 8000})
+
+	// Act
+	req := adminRequest("POST", "/api/v1/admin/tiers/simulate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data []domain.TierSimulation `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	suite.NoError(err)
+	suite.Equal([]domain.TierSimulation{
+		{MembershipType: "Bronze", CurrentCount: 2, SimulatedCount: 1, MovingIn: 0, MovingOut: 1},
+		{MembershipType: "Silver", CurrentCount: 2, SimulatedCount: 2, MovingIn: 1, MovingOut: 1},
+		{MembershipType: "Gold", CurrentCount: 1, SimulatedCount: 2, MovingIn: 1, MovingOut: 0},
+	}, response.Data)
+
+	// Nothing is applied
+	var gold int64
+	suite.db.Model(&domain.User{}).Where("membership_type = ?", "Gold").Count(&gold)
+	suite.Equal(int64(1), gold)
+}
+
+func (suite *APITestSuite) TestSimulateTiers_InvalidThresholds() {
+	// Arrange
+	body, _ := json.Marshal(domain.TierThresholds{SilverMinPoints: 8000, GoldMinPoints: 3000})
+
+	// Act
+	req := adminRequest("POST", "/api/v1/admin/tiers/simulate", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestGetUsers_MembershipTypeFilter() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", MembershipType: "Gold"},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", MembershipType: "Silver"},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipID: "LBK123458", MembershipType: "Bronze"},
+		{FirstName: "Joe", LastName: "Black", Email: "joe@example.com", MembershipID: "LBK123459", MembershipType: "Gold"},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	list := func(url string) ([]string, int64) {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data       []domain.User `json:"data"`
+			Pagination struct {
+				Total int64 `json:"total"`
+			} `json:"pagination"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		var result []string
+		for _, user := range response.Data {
+			result = append(result, user.Email)
+		}
+		return result, response.Pagination.Total
+	}
+
+	// Act
+	gold, _ := list("/api/v1/users?membership_type=Gold")
+	goldSilver, _ := list("/api/v1/users?membership_type=Gold,Silver")
+	page, total := list("/api/v1/users?membership_type=Gold&page=2&limit=1")
+
+	// Assert
+	suite.Equal([]string{"john@example.com", "joe@example.com"}, gold)
+	suite.Equal([]string{"john@example.com", "jane@example.com", "joe@example.com"}, goldSilver)
+	suite.Equal([]string{"joe@example.com"}, page)
+	suite.Equal(int64(2), total)
+}
+
+func (suite *APITestSuite) TestGetUsers_InvalidMembershipTypeFilter() {
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users?membership_type=Gold,Platinum", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var response errorResponse
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Contains(response.Error.Message, "invalid membership type")
+}
+
+func (suite *APITestSuite) TestCreateUser_InvalidMembershipType() {
+	// Arrange
+	body, err := json.Marshal(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Platinum"})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var response errorResponse
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(handler.ErrorBody{
+		Code:    "INVALID_MEMBERSHIP_TYPE",
+		Message: "invalid membership type: Platinum, must be one of Bronze, Silver, Gold",
+	}, response.Error)
+}
+
+func (suite *APITestSuite) TestSearchUsers() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "สมชาย", LastName: "ใจดี", Email: "somchai@example.com", MembershipID: "LBK123456"},
+		{FirstName: "สมหญิง", LastName: "รักดี", Email: "somying@example.com", MembershipID: "LBK123457"},
+		{FirstName: "John", LastName: "Doe", Email: "john_doe@example.com", MembershipID: "LBK123458"},
+		{FirstName: "Jane", LastName: "Smith", Email: "janesmith@example.com", MembershipID: "LBK123459"},
+	}
+	for _, user := range users {
+		suite.Require().NoError(suite.db.Create(&user).Error)
+	}
+
+	search := func(url string) ([]string, int64) {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data       []domain.User `json:"data"`
+			Pagination struct {
+				Total int64 `json:"total"`
+			} `json:"pagination"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		var result []string
+		for _, user := range response.Data {
+			result = append(result, user.Email)
+		}
+		return result, response.Pagination.Total
+	}
+
+	// Act
+	thai, _ := search("/api/v1/users/search?q=" + url.QueryEscape("ดี"))
+	caseInsensitive, _ := search("/api/v1/users/search?q=SOMCHAI")
+	wildcard, _ := search("/api/v1/users/search?q=_")
+	page, total := search("/api/v1/users/search?q=example&page=2&limit=3")
+
+	// Assert
+	suite.Equal([]string{"somchai@example.com", "somying@example.com"}, thai)
+	suite.Equal([]string{"somchai@example.com"}, caseInsensitive)
+	suite.Equal([]string{"john_doe@example.com"}, wildcard)
+	suite.Equal([]string{"janesmith@example.com"}, page)
+	suite.Equal(int64(4), total)
+}
+
+func (suite *APITestSuite) TestSearchUsers_EmptyQuery() {
+	for _, url := range []string{"/api/v1/users/search", "/api/v1/users/search?q=%20%20"} {
+		// Act
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+
+		// Assert
+		suite.NoError(err)
+		suite.Equal(400, resp.StatusCode, url)
+
+		var response errorResponse
+		suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+		suite.Equal(handler.ErrorBody{Code: "EMPTY_SEARCH_QUERY", Message: "search query is required"}, response.Error)
+	}
+}
+
+func (suite *APITestSuite) TestCreateUser_UniqueMembershipIDs() {
+	// Arrange
+	const total = 1000
+	seen := make(map[string]bool, total)
+
+	for i := 0; i < total; i++ {
+		body, _ := json.Marshal(domain.CreateUserRequest{
+			FirstName: "Load",
+			LastName:  "Test",
+			Email:     fmt.Sprintf("load%d@example.com", i),
+		})
+		req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Equal(201, resp.StatusCode)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+
+		// Assert
+		suite.Require().False(seen[response.Data.MembershipID], "duplicate membership ID %s", response.Data.MembershipID)
+		seen[response.Data.MembershipID] = true
+	}
+	suite.Len(seen, total)
+}
+
+func (suite *APITestSuite) TestReissueCard() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	verify := func(membershipID string) int {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/verify-card/"+membershipID, nil))
+		suite.Require().NoError(err)
+		return resp.StatusCode
+	}
+	suite.Require().Equal(200, verify("LBK123456"))
+
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/card/reissue", user.ID), nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data domain.MembershipIDChange `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal("LBK123456", response.Data.OldID)
+	suite.NotEqual("LBK123456", response.Data.NewID)
+
+	suite.Equal(403, verify("LBK123456"))
+	suite.Equal(200, verify(response.Data.NewID))
+	suite.Equal(404, verify("LBK999999"))
+}
+
+func (suite *APITestSuite) TestReissueCard_UserNotFound() {
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("POST", "/api/v1/users/999/card/reissue", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(404, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestGetUser_ComputedFields() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 7000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", Points: 9000},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+
+	get := func(query string) map[string]interface{} {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d%s", users[0].ID, query), nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response map[string]interface{}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response
+	}
+
+	// Act
+	plain := get("")
+	computed := get("?compute=points_to_next_tier,rank")
+
+	// Assert
+	suite.NotContains(plain, "computed")
+	suite.Equal(map[string]interface{}{
+		"points_to_next_tier": float64(3000),
+		"rank":                float64(2),
+	}, computed["computed"])
+}
+
+func (suite *APITestSuite) TestGetUser_UnknownComputedField() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("GET", fmt.Sprintf("/api/v1/users/%d?compute=rank,net_worth", user.ID), nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	var response errorResponse
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Contains(response.Error.Message, "unknown computed field")
+}
+
+func (suite *APITestSuite) TestImportPoints() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 4000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", Points: 100},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+	csvBody := "membership_id,delta,reason\nLBK123456,1500,partner-2026-09\nLBK123457,-200,partner-2026-09\n"
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/points/import", strings.NewReader(csvBody))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(207, resp.StatusCode)
+
+	var response struct {
+		Data    []domain.ImportRowResult `json:"data"`
+		Applied int                      `json:"applied"`
+		Failed  int                      `json:"failed"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(1, response.Applied)
+	suite.Equal(1, response.Failed)
+	suite.Equal(domain.ImportRowApplied, response.Data[0].Status)
+	suite.Equal(domain.ImportRowError, response.Data[1].Status)
+	suite.Equal("points must not be negative", response.Data[1].Error)
+
+	var john, jane domain.User
+	suite.NoError(suite.db.First(&john, users[0].ID).Error)
+	suite.NoError(suite.db.First(&jane, users[1].ID).Error)
+	suite.Equal(5500, john.Points)
+	suite.Equal("Silver", john.MembershipType)
+	suite.Equal(100, jane.Points)
+
+	var txn domain.PointsTransaction
+	suite.NoError(suite.db.Where("user_id = ?", john.ID).First(&txn).Error)
+	suite.Equal(1500, txn.Delta)
+	suite.Equal("partner-2026-09", txn.Reason)
+}
+
+func (suite *APITestSuite) TestImportPoints_PointsFloor() {
+	// Arrange - Gold members never drop below 1000 points
+	suite.config.PointsFloors = map[string]int{"Gold": 1000}
+	defer func() { suite.config.PointsFloors, suite.config.PointsFloorPolicy = nil, "" }()
+
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", MembershipType: "Gold", Points: 1500}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	importPoints := func(policy string) []domain.ImportRowResult {
+		suite.config.PointsFloorPolicy = policy
+		req := httptest.NewRequest("POST", "/api/v1/users/points/import", strings.NewReader("membership_id,delta\nLBK123456,-1000\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+
+		var response struct {
+			Data []domain.ImportRowResult `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data
+	}
+
+	// Act
+	rejected := importPoints(config.PointsFloorReject)
+	clamped := importPoints(config.PointsFloorClamp)
+
+	// Assert
+	suite.Equal(domain.ImportRowError, rejected[0].Status)
+	suite.Contains(rejected[0].Error, "tier floor")
+	suite.Equal(domain.ImportRowApplied, clamped[0].Status)
+
+	var stored domain.User
+	suite.NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal(1000, stored.Points)
+}
+
+func (suite *APITestSuite) TestImportPoints_Atomic() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 4000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457", Points: 100},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+	csvBody := "membership_id,delta\nLBK123456,1500\nLBK123457,-200\n"
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users/points/import?mode=atomic", strings.NewReader(csvBody))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(422, resp.StatusCode)
+
+	var response struct {
+		Data []domain.ImportRowResult `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(domain.ImportRowSkipped, response.Data[0].Status)
+	suite.Equal(domain.ImportRowError, response.Data[1].Status)
+
+	var john domain.User
+	suite.NoError(suite.db.First(&john, users[0].ID).Error)
+	suite.Equal(4000, john.Points)
+
+	var count int64
+	suite.db.Model(&domain.PointsTransaction{}).Count(&count)
+	suite.Zero(count)
+}
+
+func (suite *APITestSuite) TestRestoreUser() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	resp, err := suite.app.Test(httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil))
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, resp.StatusCode)
+
+	count := func(url string) int {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		var response struct {
+			Count int `json:"count"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Count
+	}
+	suite.Equal(0, count("/api/v1/users"))
+	suite.Equal(1, count("/api/v1/users?include_deleted=true"))
+
+	// Act
+	resp, err = suite.app.Test(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/restore", user.ID), nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data domain.User `json:"data"`
+	}
+	suite.NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal("john@example.com", response.Data.Email)
+	suite.False(response.Data.DeletedAt.Valid)
+	suite.Equal(1, count("/api/v1/users"))
+
+	resp, err = suite.app.Test(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/restore", user.ID), nil))
+	suite.NoError(err)
+	suite.Equal(404, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestDeleteUser_EmailReserved() {
+	// Arrange - by default deleted users keep their email
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	resp, err := suite.app.Test(httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil))
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, resp.StatusCode)
+
+	body, _ := json.Marshal(domain.CreateUserRequest{FirstName: "Johnny", LastName: "Doe", Email: "john@example.com"})
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
+
+	// The deleted user can still come back with the email
+	resp, err = suite.app.Test(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/restore", user.ID), nil))
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestDeleteUser_EmailReusable() {
+	// Arrange
+	suite.config.UniqueExcludesDeleted = true
+	defer func() { suite.config.UniqueExcludesDeleted = false }()
+
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+	resp, err := suite.app.Test(httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/users/%d", user.ID), nil))
+	suite.Require().NoError(err)
+	suite.Require().Equal(200, resp.StatusCode)
+
+	body, _ := json.Marshal(domain.CreateUserRequest{FirstName: "Johnny", LastName: "Doe", Email: "john@example.com"})
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(201, resp.StatusCode)
+
+	// The deleted user cannot come back while the new one holds the email
+	resp, err = suite.app.Test(httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/restore", user.ID), nil))
+	suite.NoError(err)
+	suite.Equal(409, resp.StatusCode)
+}
+
+func (suite *APITestSuite) TestCreateUser_IdempotencyKey() {
+	// Arrange
+	body, _ := json.Marshal(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"})
+	create := func(key string, body []byte) (*http.Response, domain.User) {
+		req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+		return resp, response.Data
+	}
+
+	// Act
+	first, created := create("retry-1", body)
+	retry, replayed := create("retry-1", body)
+	other, _ := create("retry-1", []byte(`{"first_name": "Jane", "last_name": "Doe", "email": "jane@example.com"}`))
+
+	// Assert
+	suite.Equal(201, first.StatusCode)
+	suite.Equal(201, retry.StatusCode)
+	suite.Equal("true", retry.Header.Get("Idempotent-Replayed"))
+	suite.Equal(created.ID, replayed.ID)
+	suite.Equal(created.MembershipID, replayed.MembershipID)
+	suite.Equal(422, other.StatusCode)
+
+	var count int64
+	suite.Require().NoError(suite.db.Model(&domain.User{}).Count(&count).Error)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *APITestSuite) TestCreateUser_IdempotencyKeyAfterDelete() {
+	// Arrange
+	body, _ := json.Marshal(domain.CreateUserRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"})
+	create := func() *http.Response {
+		req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "retry-1")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		return resp
+	}
+
+	first := create()
+	suite.Require().Equal(201, first.StatusCode)
+	var created struct {
+		Data domain.User `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(first.Body).Decode(&created))
+	suite.Require().NoError(suite.db.Delete(&domain.User{}, created.Data.ID).Error)
+
+	// Act
+	retry := create()
+
+	// Assert
+	suite.Equal(410, retry.StatusCode)
+	var response struct {
+		Error handler.ErrorBody `json:"error"`
+	}
+	suite.Require().NoError(json.NewDecoder(retry.Body).Decode(&response))
+	suite.Equal("IDEMPOTENT_USER_DELETED", response.Error.Code)
+	suite.Empty(retry.Header.Get("Idempotent-Replayed"))
+}
+
+func (suite *APITestSuite) TestSuspendAndReactivateUser() {
+	// Arrange
+	john := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"}
+	jane := domain.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457"}
+	suite.Require().NoError(suite.db.Create(&john).Error)
+	suite.Require().NoError(suite.db.Create(&jane).Error)
+
+	post := func(url string) (int, domain.User) {
+		resp, err := suite.app.Test(httptest.NewRequest("POST", url, nil))
+		suite.Require().NoError(err)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&response)
+		return resp.StatusCode, response.Data
+	}
+	list := func(url string) map[string]string {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", url, nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data []domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		statuses := map[string]string{}
+		for _, user := range response.Data {
+			statuses[user.Email] = user.Status
+		}
+		return statuses
+	}
+
+	// Act
+	suspendStatus, suspended := post(fmt.Sprintf("/api/v1/users/%d/suspend", john.ID))
+	all := list("/api/v1/users")
+	active := list("/api/v1/users?status=active")
+	reactivateStatus, reactivated := post(fmt.Sprintf("/api/v1/users/%d/reactivate", john.ID))
+	missingStatus, _ := post("/api/v1/users/999/suspend")
+	invalidFilter, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users?status=banned", nil))
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(200, suspendStatus)
+	suite.Equal(domain.UserStatusSuspended, suspended.Status)
+	suite.Equal(map[string]string{"john@example.com": "suspended", "jane@example.com": "active"}, all)
+	suite.Equal(map[string]string{"jane@example.com": "active"}, active)
+	suite.Equal(200, reactivateStatus)
+	suite.Equal(domain.UserStatusActive, reactivated.Status)
+	suite.Equal(404, missingStatus)
+	suite.Equal(400, invalidFilter.StatusCode)
+}
+
+func (suite *APITestSuite) TestPointsChange_SuspendedUser() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100, Status: domain.UserStatusSuspended}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	send := func(method, url, contentType, body string) (int, []byte) {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		raw, err := io.ReadAll(resp.Body)
+		suite.Require().NoError(err)
+		return resp.StatusCode, raw
+	}
+
+	// Act
+	addStatus, addBody := send("POST", fmt.Sprintf("/api/v1/users/%d/points", user.ID), "application/json", `{"delta":50}`)
+	setStatus, _ := send("PUT", fmt.Sprintf("/api/v1/users/%d/points", user.ID), "application/json", `{"points":500}`)
+	importStatus, importBody := send("POST", "/api/v1/users/points/import", "text/csv", "membership_id,delta\nLBK123456,50\n")
+
+	// Assert
+	suite.Equal(409, addStatus)
+	suite.Contains(string(addBody), "USER_SUSPENDED")
+	suite.Equal(409, setStatus)
+	suite.Equal(400, importStatus)
+	suite.Contains(string(importBody), domain.ErrUserSuspended.Error())
+
+	var stored domain.User
+	suite.Require().NoError(suite.db.First(&stored, user.ID).Error)
+	suite.Equal(100, stored.Points)
+}
+
+func (suite *APITestSuite) TestAddPoints() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456", Points: 100}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	add := func(body string) (int, domain.User) {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/points", user.ID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+
+		var response struct {
+			Data domain.User `json:"data"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response.Data
+	}
+
+	// Act
+	addStatus, added := add(`{"delta": 500}`)
+	subtractStatus, subtracted := add(`{"delta": -200}`)
+	negativeStatus, _ := add(`{"delta": -1000}`)
+	missingStatus, _ := add(`{}`)
+
+	// Assert
+	suite.Equal(200, addStatus)
+	suite.Equal(600, added.Points)
+	suite.Equal(200, subtractStatus)
+	suite.Equal(400, subtracted.Points)
+	suite.Equal(400, negativeStatus)
+	suite.Equal(400, missingStatus)
+}
+
+func (suite *APITestSuite) TestAddPoints_PromotesTier() {
+	// Arrange
+	suite.config.SilverMinPoints, suite.config.GoldMinPoints = 10000, 25000
+	defer func() { suite.config.SilverMinPoints, suite.config.GoldMinPoints = 0, 0 }()
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Bronze", MembershipID: "LBK123456", Points: 9800}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	// Act
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/points", user.ID), strings.NewReader(`{"delta": 500}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(200, resp.StatusCode)
+	var response struct {
+		Data domain.User `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(10300, response.Data.Points)
+	suite.Equal("Silver", response.Data.MembershipType)
+
+	var history []domain.TierChange
+	suite.Require().NoError(suite.db.Where("user_id = ?", user.ID).Find(&history).Error)
+	suite.Require().Len(history, 1)
+	suite.Equal("Bronze", history[0].From)
+	suite.Equal("Silver", history[0].To)
+}
+
+func (suite *APITestSuite) TestGetUserByMembershipID() {
+	// Arrange
+	user := domain.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK001234"}
+	suite.Require().NoError(suite.db.Create(&user).Error)
+
+	// Act
+	found, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/by-membership/LBK001234", nil))
+	suite.Require().NoError(err)
+	missing, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/by-membership/LBK999999", nil))
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(200, found.StatusCode)
+	var response struct {
+		Data domain.User `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(found.Body).Decode(&response))
+	suite.Equal(user.ID, response.Data.ID)
+	suite.Equal(404, missing.StatusCode)
+}
+
+func (suite *APITestSuite) TestGetUsersByMembershipIDs() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK000001"},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK000002"},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+
+	fetch := func(body string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/v1/users/batch/by-membership", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		return resp
+	}
+
+	// Act
+	resp := fetch(`{"membership_ids": ["LBK000002", "lbk000001", "LBK999999"]}`)
+	invalid := fetch(`{"membership_ids": ["not-a-card"]}`)
+
+	// Assert
+	suite.Equal(200, resp.StatusCode)
+	var response struct {
+		Data     []domain.User `json:"data"`
+		Count    int           `json:"count"`
+		NotFound []string      `json:"not_found"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(2, response.Count)
+	suite.Equal(users[1].ID, response.Data[0].ID)
+	suite.Equal(users[0].ID, response.Data[1].ID)
+	suite.Equal([]string{"LBK999999"}, response.NotFound)
+	suite.Equal(400, invalid.StatusCode)
+}
+
+func (suite *APITestSuite) TestNormalizeContacts() {
+	// Arrange
+	body, err := json.Marshal(domain.NormalizeRequest{
+		Emails: []string{"John@Example.com", "not-an-email"},
+		Phones: []string{"081-234-5678"},
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/v1/utils/normalize", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+
+	var response struct {
+		Data domain.NormalizeResult `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Require().Len(response.Data.Emails, 2)
+	suite.Equal("john@example.com", response.Data.Emails[0].Normalized)
+	suite.True(response.Data.Emails[0].Valid)
+	suite.False(response.Data.Emails[1].Valid)
+	suite.Require().Len(response.Data.Phones, 1)
+	suite.Equal("0812345678", response.Data.Phones[0].Normalized)
+	suite.True(response.Data.Phones[0].Valid)
+}
+
+func (suite *APITestSuite) TestGetDashboard() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipType: "Gold", MembershipID: "LBK123456", Points: 15000},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipType: "Silver", MembershipID: "LBK123457", Points: 7000},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipType: "Bronze", MembershipID: "LBK123458", Points: 100, JoinDate: time.Now().AddDate(-1, 0, 0)},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+
+	// Act
+	resp, err := suite.app.Test(adminRequest("GET", "/api/v1/admin/dashboard", nil))
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(200, resp.StatusCode)
+	var response struct {
+		Data domain.Dashboard `json:"data"`
+	}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	suite.Equal(int64(3), response.Data.TotalUsers)
+	suite.Equal(map[string]int64{"Bronze": 1, "Silver": 1, "Gold": 1}, response.Data.TierCounts)
+	suite.Equal(int64(2), response.Data.NewThisMonth)
+	suite.Equal(int64(22100), response.Data.TotalPoints)
+	suite.Require().Len(response.Data.TopUsers, 3)
+	suite.Equal(users[0].ID, response.Data.TopUsers[0].ID)
+}
+
+func (suite *APITestSuite) TestAdminRoutes_RequireAdminKey() {
+	for _, path := range []string{"/api/v1/admin/config", "/api/v1/admin/dashboard"} {
+		// Act
+		resp, err := suite.app.Test(httptest.NewRequest("GET", path, nil))
+
+		// Assert
+		suite.NoError(err)
+		suite.Equal(401, resp.StatusCode, path)
+	}
+}
+
+func (suite *APITestSuite) TestGetPointsHistory() {
+	// Arrange
+	send := func(method, url, body string) []byte {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Less(resp.StatusCode, 300)
+		data, err := io.ReadAll(resp.Body)
+		suite.Require().NoError(err)
+		return data
+	}
+	var created struct {
+		Data domain.User `json:"data"`
+	}
+	suite.Require().NoError(json.Unmarshal(send("POST", "/api/v1/users", `{"first_name": "John", "last_name": "Doe", "email": "john@example.com", "points": 300}`), &created))
+	id := created.Data.ID
+	send("POST", fmt.Sprintf("/api/v1/users/%d/points", id), `{"delta": 500}`)
+	send("PUT", fmt.Sprintf("/api/v1/users/%d", id), `{"points": 1000}`)
+	send("PUT", fmt.Sprintf("/api/v1/users/%d/points", id), `{"points": 250}`)
+	send("PUT", fmt.Sprintf("/api/v1/users/%d", id), `{"first_name": "Johnny"}`)
+
+	// Act
+	var response struct {
+		Data  []domain.PointsTransaction `json:"data"`
+		Count int                        `json:"count"`
+	}
+	suite.Require().NoError(json.Unmarshal(send("GET", fmt.Sprintf("/api/v1/users/%d/points/history", id), ""), &response))
+	missing, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/9999/points/history", nil))
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(4, response.Count)
+	reasons := []string{domain.PointsReasonInitial, domain.PointsReasonAdd, domain.PointsReasonUpdate, domain.PointsReasonSet}
+	total := 0
+	for i, txn := range response.Data {
+		total += txn.Delta
+		suite.Equal(reasons[i], txn.Reason)
+		suite.Equal(total, txn.Balance)
+	}
+	var user domain.User
+	suite.Require().NoError(suite.db.First(&user, id).Error)
+	suite.Equal(user.Points, total)
+	suite.Equal(404, missing.StatusCode)
+}
+
+func (suite *APITestSuite) TestGetUserChanges() {
+	// Arrange
+	users := []domain.User{
+		{FirstName: "John", LastName: "Doe", Email: "john@example.com", MembershipID: "LBK123456"},
+		{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com", MembershipID: "LBK123457"},
+		{FirstName: "Jim", LastName: "Beam", Email: "jim@example.com", MembershipID: "LBK123458"},
+	}
+	for i := range users {
+		suite.Require().NoError(suite.db.Create(&users[i]).Error)
+	}
+	since := time.Now()
+
+	send := func(method, url, body string) {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Require().Less(resp.StatusCode, 300)
+	}
+	send("PUT", fmt.Sprintf("/api/v1/users/%d", users[0].ID), `{"first_name": "Johnny"}`)
+	send("DELETE", fmt.Sprintf("/api/v1/users/%d", users[1].ID), "")
+	send("POST", "/api/v1/users", `{"first_name": "Joe", "last_name": "Black", "email": "joe@example.com"}`)
+
+	changes := func(since string) ([]domain.UserChange, string) {
+		resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/changes?since="+url.QueryEscape(since), nil))
+		suite.Require().NoError(err)
+		suite.Require().Equal(200, resp.StatusCode)
+
+		var response struct {
+			Data      []domain.UserChange `json:"data"`
+			NextSince string              `json:"next_since"`
+		}
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+		return response.Data, response.NextSince
+	}
+
+	// Act
+	delta, next := changes(since.Format(time.RFC3339Nano))
+	empty, _ := changes(next)
+
+	// Assert
+	suite.Require().Len(delta, 3)
+	suite.Equal("Johnny", delta[0].FirstName)
+	suite.False(delta[0].Deleted)
+	suite.Equal("jane@example.com", delta[1].Email)
+	suite.True(delta[1].Deleted)
+	suite.Equal("joe@example.com", delta[2].Email)
+	suite.False(delta[2].Deleted)
+	suite.Empty(empty)
+}
+
+func (suite *APITestSuite) TestGetUserChanges_InvalidSince() {
+	// Act
+	resp, err := suite.app.Test(httptest.NewRequest("GET", "/api/v1/users/changes?since=yesterday", nil))
+
+	// Assert
+	suite.NoError(err)
+	suite.Equal(400, resp.StatusCode)
 }
 
 func TestAPITestSuite(t *testing.T) {